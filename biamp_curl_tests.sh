@@ -8,6 +8,26 @@ MICROSERVICE_URL="localhost:8080"
 DEVICE_FQDN="biamp-device.local"
 INSTANCE_TAG="main"
 PRESET_ID="1"
+RAW_TOKEN="changeme"
+MIXER_TAG="Mixer1"
+EQ_TAG="PEQ1"
+DYNAMICS_TAG="Compressor1"
+AEC_TAG="AecInput1"
+AUTOMIXER_TAG="AutoMixer1"
+DUCKER_TAG="Ducker1"
+DELAY_TAG="Delay1"
+TONE_TAG="ToneGenerator1"
+LOGIC_INPUT_TAG="LogicInput1"
+LOGIC_OUTPUT_TAG="LogicOutput1"
+INPUT_TAG="Input1"
+OUTPUT_TAG="Output1"
+USB_TAG="UsbInput1"
+STREAM_TAG="DanteIn1"
+BLUETOOTH_TAG="Bluetooth1"
+COMBINER_TAG="RoomCombiner1"
+COMMAND_TAG="CommandString1"
+ANC_TAG="ANC1"
+PARLE_TAG="TCM1"
 
 echo "Starting Biamp Microservice API Tests..."
 echo "Microservice URL: $MICROSERVICE_URL"
@@ -40,6 +60,141 @@ echo "Testing GET Audiomode..."
 curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/audiomode/$INSTANCE_TAG"
 sleep 1
 
+# GET Aliases
+echo "Testing GET Aliases..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/aliases"
+sleep 1
+
+# GET Aliases with block type probing
+echo "Testing GET Aliases (probe)..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/aliases/probe"
+sleep 1
+
+# GET Block attribute
+echo "Testing GET Block attribute..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/block/$INSTANCE_TAG/level,1"
+sleep 1
+
+# GET Mixer input level
+echo "Testing GET Mixer input level..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/mixerinputlevel/$MIXER_TAG/1"
+sleep 1
+
+# GET Mixer crosspoint
+echo "Testing GET Mixer crosspoint..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/mixercrosspoint/$MIXER_TAG/1"
+sleep 1
+
+# GET PEQ band gain
+echo "Testing GET PEQ band gain..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/peqgain/$EQ_TAG/1"
+sleep 1
+
+# GET Dynamics
+echo "Testing GET Dynamics..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/dynamics/$DYNAMICS_TAG"
+sleep 1
+
+# GET AEC
+echo "Testing GET AEC..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/aec/$AEC_TAG/1"
+sleep 1
+
+# GET Automixer gate
+echo "Testing GET Automixer gate..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/automixergate/$AUTOMIXER_TAG/1"
+sleep 1
+
+# GET Events
+echo "Testing GET Events..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/events"
+sleep 1
+
+# GET Ducker level
+echo "Testing GET Ducker level..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/duckerlevel/$DUCKER_TAG"
+sleep 1
+
+# GET Delay
+echo "Testing GET Delay..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/delay/$DELAY_TAG/1"
+sleep 1
+
+# GET Tone frequency
+echo "Testing GET Tone frequency..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/tonefrequency/$TONE_TAG"
+sleep 1
+
+# GET Logic input
+echo "Testing GET Logic input..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/logicinput/$LOGIC_INPUT_TAG/1"
+sleep 1
+
+# GET Input preamp gain
+echo "Testing GET Input preamp gain..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/inputgain/$INPUT_TAG/1"
+sleep 1
+
+# GET Output invert
+echo "Testing GET Output invert..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/outputinvert/$OUTPUT_TAG/1"
+sleep 1
+
+# GET Labels
+echo "Testing GET Labels..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/labels/$INSTANCE_TAG"
+sleep 1
+
+# GET Mixer output labels
+echo "Testing GET Mixer output labels..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/labels/$MIXER_TAG/output"
+sleep 1
+
+# GET Channel counts
+echo "Testing GET Channel counts..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/channels/$INSTANCE_TAG"
+sleep 1
+
+# GET USB status
+echo "Testing GET USB status..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/usbstatus/$USB_TAG"
+sleep 1
+
+# GET Stream status
+echo "Testing GET Stream status..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/streamstatus/$STREAM_TAG"
+sleep 1
+
+# GET Network status
+echo "Testing GET Network status..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/networkstatus"
+sleep 1
+
+# GET Bluetooth state
+echo "Testing GET Bluetooth state..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/bluetooth/$BLUETOOTH_TAG"
+sleep 1
+
+# GET Room combiner groups
+echo "Testing GET Room combiner groups..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/combinergroups/$COMBINER_TAG"
+sleep 1
+
+# GET Command strings
+echo "Testing GET Command strings..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/commandstrings/$COMMAND_TAG"
+sleep 1
+
+# GET ANC
+echo "Testing GET ANC..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/anc/$ANC_TAG"
+sleep 1
+
+# GET Parle talker position
+echo "Testing GET Parle talker position..."
+curl -X GET "http://$MICROSERVICE_URL/$DEVICE_FQDN/parletalker/$PARLE_TAG"
+sleep 1
+
 echo "=============================================="
 echo "Starting SET/PUT operations..."
 echo "=============================================="
@@ -86,6 +241,181 @@ curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/audiomode/$INSTANCE_TAG" \
      -d "\"1\""
 sleep 1
 
+# SET Block attribute
+echo "Testing SET Block attribute (-20)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/block/$INSTANCE_TAG/level,1" \
+     -H "Content-Type: application/json" \
+     -d "\"-20\""
+sleep 1
+
+# SET Raw command (needs BIAMP_RAW_TOKEN set on the microservice)
+echo "Testing SET Raw command..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/raw" \
+     -H "Content-Type: application/json" \
+     -d "{\"token\": \"$RAW_TOKEN\", \"command\": \"$INSTANCE_TAG get level 1\"}"
+sleep 1
+
+# SET Mixer input level
+echo "Testing SET Mixer input level (50)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/mixerinputlevel/$MIXER_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"50\""
+sleep 1
+
+# SET Mixer crosspoint
+echo "Testing SET Mixer crosspoint (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/mixercrosspoint/$MIXER_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET PEQ band gain
+echo "Testing SET PEQ band gain (-3)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/peqgain/$EQ_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"-3\""
+sleep 1
+
+# SET PEQ block bypass
+echo "Testing SET PEQ block bypass (false)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/peqbypass/$EQ_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"false\""
+sleep 1
+
+# SET Dynamics threshold
+echo "Testing SET Dynamics threshold (-20)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/dynamics/$DYNAMICS_TAG/threshold" \
+     -H "Content-Type: application/json" \
+     -d "\"-20\""
+sleep 1
+
+# SET AEC NLP mode
+echo "Testing SET AEC NLP mode (MEDIUM)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/aecnlpmode/$AEC_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"MEDIUM\""
+sleep 1
+
+# SET Automixer channel
+echo "Testing SET Automixer channel (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/automixerchannel/$AUTOMIXER_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Ducker level
+echo "Testing SET Ducker level (-20)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/duckerlevel/$DUCKER_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"-20\""
+sleep 1
+
+# SET Delay
+echo "Testing SET Delay (40ft)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/delay/$DELAY_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"40ft\""
+sleep 1
+
+# SET Tone frequency
+echo "Testing SET Tone frequency (1000)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/tonefrequency/$TONE_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"1000\""
+sleep 1
+
+# SET Tone mute
+echo "Testing SET Tone mute (false)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/tonemute/$TONE_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"false\""
+sleep 1
+
+# SET Logic output
+echo "Testing SET Logic output (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/logicoutput/$LOGIC_OUTPUT_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Input preamp gain
+echo "Testing SET Input preamp gain (36)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/inputgain/$INPUT_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"36\""
+sleep 1
+
+# SET Input phantom power
+echo "Testing SET Input phantom power (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/inputphantom/$INPUT_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Output level
+echo "Testing SET Output level (-10)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/outputlevel/$OUTPUT_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"-10\""
+sleep 1
+
+# SET USB mute
+echo "Testing SET USB mute (false)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/usbmute/$USB_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"false\""
+sleep 1
+
+# SET Bluetooth discoverable
+echo "Testing SET Bluetooth discoverable (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/bluetoothdiscoverable/$BLUETOOTH_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Bluetooth disconnect
+echo "Testing SET Bluetooth disconnect (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/bluetoothdisconnect/$BLUETOOTH_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Room combiner wall
+echo "Testing SET Room combiner wall (open)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/combinerwall/$COMBINER_TAG/1" \
+     -H "Content-Type: application/json" \
+     -d "\"open\""
+sleep 1
+
+# SET Command string trigger
+echo "Testing SET Command string trigger (1)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/commandstring/$COMMAND_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"1\""
+sleep 1
+
+# SET ANC bypass
+echo "Testing SET ANC bypass (false)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/ancbypass/$ANC_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"false\""
+sleep 1
+
+# SET Parle mute
+echo "Testing SET Parle mute (true)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/parlemute/$PARLE_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"true\""
+sleep 1
+
+# SET Parle LED muted color
+echo "Testing SET Parle LED muted color (RED)..."
+curl -X PUT "http://$MICROSERVICE_URL/$DEVICE_FQDN/parleledmutedcolor/$PARLE_TAG" \
+     -H "Content-Type: application/json" \
+     -d "\"RED\""
+sleep 1
+
 echo "=============================================="
 echo "All API tests completed!"
 echo "=============================================="
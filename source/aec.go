@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes of a channel of an AEC Input block. ERLE and the reference level are read only status.
+var aecAttributes = []blockAttribute{
+	{name: "aecEnable", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "nlpMode", indices: 1, valueType: "enum", readable: true, writable: true, values: []string{"OFF", "SOFT", "MEDIUM", "AGGRESSIVE"}},
+	{name: "noiseReductionEnable", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "noiseReductionLevel", indices: 1, valueType: "number", readable: true, writable: true},
+	{name: "agcEnable", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "confMode", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "erle", indices: 1, valueType: "number", readable: true},
+	{name: "refLevel", indices: 1, valueType: "number", readable: true},
+	{name: "refPresent", indices: 1, valueType: "state", readable: true},
+}
+
+// AEC settings that can be changed on their own, mapped to their TTP attribute.
+//
+//	PUT :address/aecnlpmode/:instanceTag/:channel
+var aecSettings = map[string]string{
+	"aecenable":              "aecEnable",
+	"aecnlpmode":             "nlpMode",
+	"aecnoisereduction":      "noiseReductionEnable",
+	"aecnoisereductionlevel": "noiseReductionLevel",
+	"aecagc":                 "agcEnable",
+	"aecconferencemode":      "confMode",
+}
+
+// Gets every AEC setting and status value of a channel as one JSON object, e.g.
+// {"aecEnable":true,"agcEnable":false,"confMode":false,"erle":18.5,"nlpMode":"MEDIUM",...}.
+// Status values the block doesn't expose are left out.
+func getAEC(socketKey string, instanceTag string, channel string) (string, error) {
+	function := "getAEC"
+
+	err := validateIndices([]string{channel})
+	if err != nil {
+		errMsg := function + " - 2hs8cje - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, "aec", func(policy retryPolicy) (string, error) {
+		return getAttributesObjectDo(socketKey, instanceTag, aecAttributes, []string{channel}, policy)
+	})
+}
+
+// Gets one AEC setting of a channel.
+func getAECSetting(socketKey string, setting string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getAECSetting", setting, "AEC Input", aecSettings[setting], instanceTag, channel)
+}
+
+// Sets one AEC setting of a channel so remote support can toggle it during a live call.
+func setAECSetting(socketKey string, setting string, instanceTag string, channel string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setAECSetting", setting, "AEC Input", aecSettings[setting], instanceTag, value, channel)
+}
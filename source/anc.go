@@ -0,0 +1,31 @@
+package main
+
+// Ambient noise compensation settings, mapped to their TTP attribute. Gains and levels are in dB.
+// ancgain is the gain currently applied and ancambientlevel the ambient level measured, both read only.
+//
+//	GET/PUT :address/ancbypass/:instanceTag
+var ancSettings = map[string]string{
+	"ancbypass":       "bypass",
+	"ancmingain":      "minGain",
+	"ancmaxgain":      "maxGain",
+	"ancgain":         "gain",
+	"ancambientlevel": "ambientLevel",
+}
+
+// Gets every ANC setting and read-out of the specified instance tag as a JSON object,
+// e.g. {"bypass":false,"minGain":-6,"maxGain":12,"gain":3.5,"ambientLevel":-42}.
+func getANC(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getANC", "anc", func(policy retryPolicy) (string, error) {
+		return getAttributesObjectDo(socketKey, instanceTag, blockRegistry["Ambient Noise Compensation"].attributes, nil, policy)
+	})
+}
+
+// Gets an ANC setting of the specified instance tag.
+func getANCSetting(socketKey string, setting string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getANCSetting", setting, "Ambient Noise Compensation", ancSettings[setting], instanceTag)
+}
+
+// Sets an ANC setting of the specified instance tag. Setting ancbypass to true turns compensation off.
+func setANCSetting(socketKey string, setting string, instanceTag string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setANCSetting", setting, "Ambient Noise Compensation", ancSettings[setting], instanceTag, value)
+}
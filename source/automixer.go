@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes shared by Gating AutoMixer and Gain Sharing AutoMixer blocks.
+// Gate status is read only and is subscribed to so that mics going live show up in GET events.
+var autoMixerAttributes = []blockAttribute{
+	{name: "channelOn", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "channelManual", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "gateStatus", indices: 1, valueType: "state", readable: true},
+	{name: "nomGainEnable", indices: 0, valueType: "state", readable: true, writable: true},
+	{name: "lastMicHoldEnable", indices: 0, valueType: "state", readable: true, writable: true},
+	{name: "inputLabel", indices: 1, valueType: "string", readable: true},
+	{name: "numInputs", indices: 0, valueType: "number", readable: true},
+}
+
+// Both automixer types have the same attributes, so either one can be used to check requests.
+const autoMixerBlockType = "Gating AutoMixer"
+
+// Returns true if the automixer channel is on.
+func getAutoMixerChannel(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getAutoMixerChannel", "automixerchannel", autoMixerBlockType, "channelOn", instanceTag, channel)
+}
+
+// Returns true if the automixer channel is in manual mode, false if it's automatic.
+func getAutoMixerManual(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getAutoMixerManual", "automixermanual", autoMixerBlockType, "channelManual", instanceTag, channel)
+}
+
+// Returns true if the automixer has the NOM (number of open mics) gain adjustment enabled.
+func getAutoMixerNOM(socketKey string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getAutoMixerNOM", "automixernom", autoMixerBlockType, "nomGainEnable", instanceTag)
+}
+
+// Returns true if the automixer holds the last mic open when nobody is talking.
+func getAutoMixerLastMicHold(socketKey string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getAutoMixerLastMicHold", "automixerlastmichold", autoMixerBlockType, "lastMicHoldEnable", instanceTag)
+}
+
+// Returns true if the automixer channel's gate is open (the mic is live).
+// The channel is subscribed to on the first call, so later gate changes are reported by GET events.
+func getAutoMixerGate(socketKey string, instanceTag string, channel string) (string, error) {
+	function := "getAutoMixerGate"
+
+	err := validateIndices([]string{channel})
+	if err != nil {
+		errMsg := function + " - 8zxe4ku - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, "automixergate", func(policy retryPolicy) (string, error) {
+		return getSubscribedStateDo(socketKey, instanceTag, "gateStatus", channel, policy)
+	})
+}
+
+// Turns an automixer channel on or off.
+func setAutoMixerChannel(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setAutoMixerChannel", "automixerchannel", autoMixerBlockType, "channelOn", instanceTag, state, channel)
+}
+
+// Puts an automixer channel in manual (true) or automatic (false) mode.
+func setAutoMixerManual(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setAutoMixerManual", "automixermanual", autoMixerBlockType, "channelManual", instanceTag, state, channel)
+}
+
+// Turns the automixer's NOM gain adjustment on or off.
+func setAutoMixerNOM(socketKey string, instanceTag string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setAutoMixerNOM", "automixernom", autoMixerBlockType, "nomGainEnable", instanceTag, state)
+}
+
+// Turns the automixer's last mic hold on or off.
+func setAutoMixerLastMicHold(socketKey string, instanceTag string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setAutoMixerLastMicHold", "automixerlastmichold", autoMixerBlockType, "lastMicHoldEnable", instanceTag, state)
+}
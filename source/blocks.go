@@ -0,0 +1,705 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Describes one attribute of a Tesira block as it appears in the Tesira Text Protocol.
+type blockAttribute struct {
+	name      string   // TTP attribute name, e.g. "level"
+	indices   int      // number of index arguments the attribute takes (0, 1 or 2)
+	valueType string   // "number", "state", "string" or "enum", matching the respTypes of sendAndValidateResponse
+	readable  bool     // can be read with get
+	writable  bool     // can be written with set
+	min       float64  // lowest value accepted by set, only checked for numbers when min < max
+	max       float64  // highest value accepted by set
+	step      float64  // numbers must be min or a whole number of steps above it when step > 0, e.g. 6 dB preamp gain steps
+	values    []string // values accepted by set for enums, any single word if empty
+}
+
+// Describes a Tesira block type and the attributes the driver knows about.
+type blockType struct {
+	name       string
+	attributes []blockAttribute
+}
+
+// Returns the named attribute of the block type.
+func (block blockType) attribute(name string) (blockAttribute, bool) {
+	for _, attribute := range block.attributes {
+		if attribute.name == name {
+			return attribute, true
+		}
+	}
+	return blockAttribute{}, false
+}
+
+// Every Tesira block type the driver knows about, keyed by the block name used in Tesira software.
+var blockRegistry = map[string]blockType{
+	"Level": {"Level", []blockAttribute{
+		{name: "level", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "minLevel", indices: 1, valueType: "number", readable: true},
+		{name: "maxLevel", indices: 1, valueType: "number", readable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+		{name: "ganged", indices: 0, valueType: "state", readable: true, writable: true},
+	}},
+	"Mute": {"Mute", []blockAttribute{
+		{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+		{name: "ganged", indices: 0, valueType: "state", readable: true, writable: true},
+	}},
+	"Logic State": {"Logic State", []blockAttribute{
+		{name: "state", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Input": {"Input", []blockAttribute{
+		{name: "gain", indices: 1, valueType: "number", readable: true, writable: true, min: 0, max: 66, step: 6},
+		{name: "phantomPower", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "level", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true, writable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Output": {"Output", []blockAttribute{
+		{name: "level", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "invert", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true, writable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Dante Input":  {"Dante Input", streamAttributes},
+	"Dante Output": {"Dante Output", streamAttributes},
+	"AVB Input":    {"AVB Input", streamAttributes},
+	"AVB Output":   {"AVB Output", streamAttributes},
+	"USB Input":    {"USB Input", usbAttributes},
+	"USB Output":   {"USB Output", usbAttributes},
+	"Logic Input": {"Logic Input", []blockAttribute{
+		{name: "state", indices: 1, valueType: "state", readable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Logic Output": {"Logic Output", []blockAttribute{
+		{name: "state", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Control Voltage": {"Control Voltage", []blockAttribute{
+		{name: "level", indices: 1, valueType: "number", readable: true, writable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Flip-Flop": {"Flip-Flop", []blockAttribute{
+		{name: "state", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Matrix Mixer": {"Matrix Mixer", []blockAttribute{
+		{name: "crosspointLevel", indices: 2, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "crosspointLevelState", indices: 2, valueType: "state", readable: true, writable: true},
+		{name: "inputLevel", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "inputMute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "outputLevel", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "outputMute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "inputLabel", indices: 1, valueType: "string", readable: true},
+		{name: "outputLabel", indices: 1, valueType: "string", readable: true},
+		{name: "numInputs", indices: 0, valueType: "number", readable: true},
+		{name: "numOutputs", indices: 0, valueType: "number", readable: true},
+	}},
+	"Standard Mixer": {"Standard Mixer", []blockAttribute{
+		{name: "crosspoint", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "inputLevel", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "inputMute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "outputLevel", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "outputMute", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "inputLabel", indices: 1, valueType: "string", readable: true},
+		{name: "numInputs", indices: 0, valueType: "number", readable: true},
+	}},
+	"Gain": {"Gain", []blockAttribute{
+		{name: "gain", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 0, valueType: "state", readable: true, writable: true},
+	}},
+	"Router": {"Router", []blockAttribute{
+		{name: "input", indices: 1, valueType: "number", readable: true, writable: true},
+		{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "inputLabel", indices: 1, valueType: "string", readable: true},
+		{name: "outputLabel", indices: 1, valueType: "string", readable: true},
+		{name: "numInputs", indices: 0, valueType: "number", readable: true},
+		{name: "numOutputs", indices: 0, valueType: "number", readable: true},
+	}},
+	"Parametric EQ": {"Parametric EQ", []blockAttribute{
+		{name: "gain", indices: 1, valueType: "number", readable: true, writable: true, min: -30, max: 15},
+		{name: "frequency", indices: 1, valueType: "number", readable: true, writable: true, min: 20, max: 20000},
+		{name: "bandwidth", indices: 1, valueType: "number", readable: true, writable: true, min: 0.01, max: 4},
+		{name: "type", indices: 1, valueType: "enum", readable: true, writable: true},
+		{name: "bypass", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "bypassAll", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "numBands", indices: 0, valueType: "number", readable: true},
+	}},
+	"Graphic EQ": {"Graphic EQ", []blockAttribute{
+		{name: "gain", indices: 1, valueType: "number", readable: true, writable: true, min: -15, max: 15},
+		{name: "frequency", indices: 1, valueType: "number", readable: true},
+		{name: "bypass", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "bypassAll", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "numBands", indices: 0, valueType: "number", readable: true},
+	}},
+	"Compressor": {"Compressor", []blockAttribute{
+		dynamicsThreshold, dynamicsRatio, dynamicsAttack, dynamicsRelease, dynamicsMakeupGain, dynamicsBypass,
+	}},
+	"Peak Limiter": {"Peak Limiter", []blockAttribute{
+		dynamicsThreshold, dynamicsAttack, dynamicsRelease, dynamicsBypass,
+	}},
+	"Leveler": {"Leveler", []blockAttribute{
+		dynamicsThreshold, dynamicsAttack, dynamicsRelease, dynamicsBypass,
+	}},
+	"AGC": {"AGC", []blockAttribute{
+		dynamicsThreshold, dynamicsAttack, dynamicsRelease, dynamicsBypass,
+	}},
+	"AEC Input":              {"AEC Input", aecAttributes},
+	"Gating AutoMixer":       {"Gating AutoMixer", autoMixerAttributes},
+	"Gain Sharing AutoMixer": {"Gain Sharing AutoMixer", autoMixerAttributes},
+	"Ducker": {"Ducker", []blockAttribute{
+		{name: "duckingLevel", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 0},
+		{name: "threshold", indices: 0, valueType: "number", readable: true, writable: true, min: -80, max: 20},
+		{name: "senseMute", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "inputMute", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "bypass", indices: 0, valueType: "state", readable: true, writable: true},
+	}},
+	"Ambient Noise Compensation": {"Ambient Noise Compensation", []blockAttribute{
+		{name: "bypass", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "minGain", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 20},
+		{name: "maxGain", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 20},
+		{name: "gain", indices: 0, valueType: "number", readable: true},
+		{name: "ambientLevel", indices: 0, valueType: "number", readable: true},
+	}},
+	"Delay": {"Delay", []blockAttribute{
+		{name: "delay", indices: 1, valueType: "number", readable: true, writable: true},
+		{name: "maxDelay", indices: 0, valueType: "number", readable: true},
+		{name: "bypass", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "numChannels", indices: 0, valueType: "number", readable: true},
+	}},
+	"Tone Generator": {"Tone Generator", []blockAttribute{
+		{name: "frequency", indices: 0, valueType: "number", readable: true, writable: true, min: 20, max: 20000},
+		{name: "level", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 0, valueType: "state", readable: true, writable: true},
+	}},
+	"Noise Generator": {"Noise Generator", []blockAttribute{
+		{name: "level", indices: 0, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "mute", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "type", indices: 0, valueType: "enum", readable: true, writable: true},
+	}},
+	"Room Combiner": {"Room Combiner", []blockAttribute{
+		{name: "wallState", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "group", indices: 1, valueType: "number", readable: true, writable: true, min: 0, step: 1},
+		{name: "sourceSelection", indices: 1, valueType: "number", readable: true, writable: true, min: 0, step: 1},
+		{name: "levelOut", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+		{name: "muteOut", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "roomLabel", indices: 1, valueType: "string", readable: true},
+		{name: "numRooms", indices: 0, valueType: "number", readable: true},
+		{name: "numWalls", indices: 0, valueType: "number", readable: true},
+		{name: "numSources", indices: 0, valueType: "number", readable: true},
+	}},
+	"Command String": {"Command String", []blockAttribute{
+		{name: "command", indices: 1, valueType: "string", readable: true},
+		{name: "label", indices: 1, valueType: "string", readable: true},
+	}},
+	"Bluetooth": {"Bluetooth", []blockAttribute{
+		{name: "discoverable", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "connected", indices: 0, valueType: "state", readable: true},
+		{name: "connectedDeviceName", indices: 0, valueType: "string", readable: true},
+		{name: "streaming", indices: 0, valueType: "state", readable: true},
+	}},
+	"Parle Microphone": {"Parle Microphone", []blockAttribute{
+		{name: "mute", indices: 0, valueType: "state", readable: true, writable: true},
+		{name: "talkerAzimuth", indices: 0, valueType: "number", readable: true},
+		{name: "talkerElevation", indices: 0, valueType: "number", readable: true},
+		{name: "ledMutedColor", indices: 0, valueType: "enum", readable: true, writable: true, values: ledColors},
+		{name: "ledUnmutedColor", indices: 0, valueType: "enum", readable: true, writable: true, values: ledColors},
+		{name: "ledBrightness", indices: 0, valueType: "number", readable: true, writable: true, min: 0, max: 100},
+		{name: "zoneEnable", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "numZones", indices: 0, valueType: "number", readable: true},
+	}},
+	"Dialer": {"Dialer", []blockAttribute{
+		{name: "autoAnswer", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "dndEnable", indices: 1, valueType: "state", readable: true, writable: true},
+		{name: "displayNameLabel", indices: 1, valueType: "string", readable: true},
+		{name: "numLines", indices: 0, valueType: "number", readable: true},
+	}},
+}
+
+// What the driver has learned about a block on a DSP.
+type blockMetadata struct {
+	blockType string                       // block type name from blockRegistry guessed by aliases/probe, or "unknown"
+	labels    map[string]map[string]string // label attribute -> index -> label
+	counts    map[string]int               // count attribute, like numChannels -> count
+}
+
+var blockMetadataCache = make(map[string]map[string]blockMetadata) // socketKey -> instance tag -> metadata
+var blockMetadataMutex sync.Mutex
+
+// Returns what is known about an instance tag on a DSP.
+func getBlockMetadata(socketKey string, instanceTag string) (blockMetadata, bool) {
+	blockMetadataMutex.Lock()
+	defer blockMetadataMutex.Unlock()
+	metadata, found := blockMetadataCache[socketKey][instanceTag]
+	return metadata, found
+}
+
+// Changes what is known about an instance tag on a DSP.
+func updateBlockMetadata(socketKey string, instanceTag string, update func(metadata *blockMetadata)) {
+	blockMetadataMutex.Lock()
+	defer blockMetadataMutex.Unlock()
+	if blockMetadataCache[socketKey] == nil {
+		blockMetadataCache[socketKey] = make(map[string]blockMetadata)
+	}
+	metadata := blockMetadataCache[socketKey][instanceTag]
+	update(&metadata)
+	blockMetadataCache[socketKey][instanceTag] = metadata
+}
+
+// Remembers the block type of an instance tag on a DSP.
+func setBlockType(socketKey string, instanceTag string, blockTypeName string) {
+	updateBlockMetadata(socketKey, instanceTag, func(metadata *blockMetadata) {
+		metadata.blockType = blockTypeName
+	})
+}
+
+// A new connection may mean a new configuration was loaded, so everything learned about the blocks is forgotten.
+func forgetBlockMetadata(socketKey string) {
+	blockMetadataMutex.Lock()
+	defer blockMetadataMutex.Unlock()
+	delete(blockMetadataCache, socketKey)
+}
+
+// Finds the attribute definition for an instance tag. The tag's block type from aliases/probe is only a guess,
+// so it is used when it has the attribute and otherwise any registered block type with a matching attribute is accepted.
+func lookupBlockAttribute(socketKey string, instanceTag string, name string, indices int) (blockAttribute, error) {
+	metadata, found := getBlockMetadata(socketKey, instanceTag)
+	if found {
+		attribute, ok := blockRegistry[metadata.blockType].attribute(name)
+		if ok && attribute.indices == indices {
+			return attribute, nil
+		}
+	}
+
+	// Searching in name order so the same attribute is found every time
+	blockNames := make([]string, 0, len(blockRegistry))
+	for blockName := range blockRegistry {
+		blockNames = append(blockNames, blockName)
+	}
+	sort.Strings(blockNames)
+
+	known := false
+	var match blockAttribute
+	matches := 0
+	for _, blockName := range blockNames {
+		attribute, ok := blockRegistry[blockName].attribute(name)
+		if ok {
+			known = true
+			if attribute.indices == indices {
+				if matches == 0 {
+					match = attribute
+				} else if !reflect.DeepEqual(attribute, match) {
+					// Block types disagree on the limits (e.g. gain on an EQ and on an input),
+					// so without knowing the block type the DSP has to be left to check the value
+					match.min, match.max, match.step, match.values = 0, 0, 0, nil
+					match.readable = match.readable || attribute.readable
+					match.writable = match.writable || attribute.writable
+				}
+				matches++
+			}
+		}
+	}
+	if matches > 0 {
+		return match, nil
+	}
+	if known {
+		return blockAttribute{}, fmt.Errorf("%s doesn't take %d indices", name, indices)
+	}
+	return blockAttribute{}, errors.New("unrecognized block attribute: " + name)
+}
+
+// Splits an attribute URI parameter such as "crosspointLevel,1,2" into the attribute name and its indices.
+func parseAttributeIndices(attributeAndIndices string) (string, []string, error) {
+	parts := strings.Split(attributeAndIndices, ",")
+	err := validateIndices(parts[1:])
+	if err != nil {
+		return parts[0], nil, err
+	}
+	return parts[0], parts[1:], nil
+}
+
+// Checks a value against the attribute's type and limits. Returns the value formatted for TTP.
+func validateAttributeValue(attribute blockAttribute, value string) (string, error) {
+	switch attribute.valueType {
+	case "number":
+		number, err := strconv.ParseFloat(value, 64)
+		// NaN fails every comparison, so it would pass the range check below
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return value, errors.New(attribute.name + " must be a number, got " + value)
+		}
+		if attribute.min < attribute.max && (number < attribute.min || number > attribute.max) {
+			return value, fmt.Errorf("%s must be between %v and %v, got %v", attribute.name, attribute.min, attribute.max, value)
+		}
+		if attribute.step > 0 && (number < attribute.min || math.Mod(number-attribute.min, attribute.step) != 0) {
+			return value, fmt.Errorf("%s must be in steps of %v from %v, got %v", attribute.name, attribute.step, attribute.min, value)
+		}
+	case "state":
+		if value != "true" && value != "false" {
+			return value, errors.New(attribute.name + " must be true or false, got " + value)
+		}
+	case "string":
+		// A CR or LF would end the command early and start a second one
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return value, errors.New(attribute.name + " can't contain control characters")
+		}
+		// TTP strings are quoted, so quotes inside the value have to be escaped
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`, nil
+	case "enum":
+		if len(attribute.values) > 0 && !slices.Contains(attribute.values, value) {
+			return value, fmt.Errorf("%s must be one of %s, got %s", attribute.name, strings.Join(attribute.values, ", "), value)
+		}
+		if value == "" || strings.ContainsAny(value, "\" \r\n") {
+			return value, errors.New(attribute.name + " must be a single word, got " + value)
+		}
+	}
+	return value, nil
+}
+
+// Checks that every index is a whole number from 1 up, which is how Tesira numbers channels and bands.
+func validateIndices(indices []string) error {
+	for _, index := range indices {
+		number, err := strconv.Atoi(index)
+		if err != nil || number < 1 {
+			return errors.New("invalid index: " + index)
+		}
+	}
+	return nil
+}
+
+// Trims the trailing zeros TTP puts on numbers, e.g. 1000.000000 becomes 1000.
+func formatNumber(value string) string {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// Reads an attribute of a known block type in real units (dB, Hz, true/false...).
+// Used by the endpoints for specific block types, which know the block type from the setting.
+func getRegisteredAttribute(socketKey string, function string, setting string, blockTypeName string, attributeName string, instanceTag string, indices ...string) (string, error) {
+	attribute, err := registeredAttribute(blockTypeName, attributeName, indices)
+	if err == nil && !attribute.readable {
+		err = errors.New(attributeName + " can't be read")
+	}
+	if err != nil {
+		errMsg := function + " - 5kqpe7c - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, setting, func(policy retryPolicy) (string, error) {
+		value, err := getAttributeDo(socketKey, instanceTag, attribute.name, indices, attribute.valueType, policy)
+		if err != nil {
+			return value, err
+		}
+		if attribute.valueType == "number" {
+			value = formatNumber(value)
+		}
+		return `"` + value + `"`, nil
+	})
+}
+
+// Writes an attribute of a known block type after checking the value against the registry's limits.
+func setRegisteredAttribute(socketKey string, function string, setting string, blockTypeName string, attributeName string, instanceTag string, value string, indices ...string) (string, error) {
+	value = strings.Trim(value, "\"")
+
+	attribute, err := registeredAttribute(blockTypeName, attributeName, indices)
+	if err == nil && !attribute.writable {
+		err = errors.New(attributeName + " can't be written")
+	}
+	if err == nil {
+		value, err = validateAttributeValue(attribute, value)
+	}
+	if err != nil {
+		errMsg := function + " - 1pz8vfe - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, setting, func(policy retryPolicy) (string, error) {
+		return setAttributeDo(socketKey, instanceTag, attribute.name, indices, value, policy)
+	})
+}
+
+// Returns an attribute of a registered block type after checking the indices given for it.
+func registeredAttribute(blockTypeName string, attributeName string, indices []string) (blockAttribute, error) {
+	attribute, found := blockRegistry[blockTypeName].attribute(attributeName)
+	if !found {
+		return attribute, errors.New(attributeName + " is not an attribute of " + blockTypeName + " blocks")
+	}
+	if len(indices) != attribute.indices {
+		return attribute, fmt.Errorf("%s takes %d indices, got %d", attributeName, attribute.indices, len(indices))
+	}
+	return attribute, validateIndices(indices)
+}
+
+// Reads any registered attribute of a block.
+// attributeAndIndices is the attribute name followed by its comma separated indices, e.g. "crosspointLevel,1,2".
+func getBlockAttribute(socketKey string, instanceTag string, attributeAndIndices string) (string, error) {
+	function := "getBlockAttribute"
+
+	name, indices, err := parseAttributeIndices(attributeAndIndices)
+	var attribute blockAttribute
+	if err == nil {
+		attribute, err = lookupBlockAttribute(socketKey, instanceTag, name, len(indices))
+	}
+	if err == nil && !attribute.readable {
+		err = errors.New(name + " can't be read")
+	}
+	if err != nil {
+		errMsg := function + " - 4vbn1qe - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, "block", func(policy retryPolicy) (string, error) {
+		value, err := getAttributeDo(socketKey, instanceTag, attribute.name, indices, attribute.valueType, policy)
+		if err != nil {
+			return value, err
+		}
+		if attribute.valueType == "number" {
+			value = formatNumber(value)
+		}
+		return `"` + value + `"`, nil
+	})
+}
+
+// Writes any registered attribute of a block after checking the value against the registry.
+func setBlockAttribute(socketKey string, instanceTag string, attributeAndIndices string, value string) (string, error) {
+	function := "setBlockAttribute"
+	value = strings.Trim(value, "\"")
+
+	name, indices, err := parseAttributeIndices(attributeAndIndices)
+	var attribute blockAttribute
+	if err == nil {
+		attribute, err = lookupBlockAttribute(socketKey, instanceTag, name, len(indices))
+	}
+	if err == nil && !attribute.writable {
+		err = errors.New(name + " can't be written")
+	}
+	if err == nil {
+		value, err = validateAttributeValue(attribute, value)
+	}
+	if err != nil {
+		errMsg := function + " - 9dmw2la - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, "block", func(policy retryPolicy) (string, error) {
+		return setAttributeDo(socketKey, instanceTag, attribute.name, indices, value, policy)
+	})
+}
+
+// Checks the parts of a command that come from a request before they are put on the wire.
+// Instance tags, attribute and service names are single words, and indices are whole numbers from 1 up.
+func checkCommandParts(instanceTag string, name string, indices []string) error {
+	for _, word := range []string{instanceTag, name} {
+		if word == "" || strings.IndexFunc(word, func(r rune) bool { return unicode.IsControl(r) || unicode.IsSpace(r) || r == '"' }) >= 0 {
+			return fmt.Errorf("%w: invalid instance tag or attribute: %q", errInvalidArgument, word)
+		}
+	}
+	err := validateIndices(indices)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// Gets an attribute of the specified instance tag. Returns the bare value, e.g. -12.5, true or Mic 1.
+func getAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, respType string, policy retryPolicy) (string, error) {
+	function := "getAttributeDo"
+
+	err := checkCommandParts(instanceTag, attribute, indices)
+	if err == nil {
+		err = checkIndexCounts(socketKey, instanceTag, attribute, indices)
+	}
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	cmdString := strings.Join(append([]string{instanceTag, "get", attribute}, indices...), " ") + "\r"
+
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", respType, policy)
+
+	if err != nil {
+		return value, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + value)
+
+	return value, nil
+}
+
+// Sets an attribute of the specified instance tag. The value must already be formatted for TTP.
+func setAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, value string, policy retryPolicy) (string, error) {
+	function := "setAttributeDo"
+
+	err := checkCommandParts(instanceTag, attribute, indices)
+	if err == nil {
+		err = checkIndexCounts(socketKey, instanceTag, attribute, indices)
+	}
+	if err == nil && strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		err = fmt.Errorf("%w: %s value can't contain control characters", errInvalidArgument, attribute)
+	}
+	if err != nil {
+		return "notok", err
+	}
+
+	cmdString := strings.Join(append([]string{instanceTag, "set", attribute}, indices...), " ") + " " + value + "\r"
+
+	resp, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
+
+	if err != nil {
+		return resp, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + resp)
+
+	forgetLabels(socketKey, instanceTag, attribute)
+
+	// If we got here, the response was good, so successful return with the state indication
+	return "ok", nil
+}
+
+// Calls a service of the specified instance tag, e.g. "Bluetooth1 disconnect". Services are actions
+// that don't set an attribute, so any arguments are passed through as they are.
+func serviceDo(socketKey string, instanceTag string, service string, args []string, policy retryPolicy) (string, error) {
+	function := "serviceDo"
+
+	err := checkCommandParts(instanceTag, service, nil)
+	for _, arg := range args {
+		if err == nil && (arg == "" || strings.IndexFunc(arg, unicode.IsControl) >= 0) {
+			err = fmt.Errorf("%w: invalid %s argument: %q", errInvalidArgument, service, arg)
+		}
+	}
+	if err != nil {
+		return "notok", err
+	}
+
+	cmdString := strings.Join(append([]string{instanceTag, service}, args...), " ") + "\r"
+
+	resp, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
+
+	if err != nil {
+		return resp, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + resp)
+
+	return "ok", nil
+}
+
+// Gets a level attribute of the specified instance tag and returns it on the 0-100 volume curve.
+func getLevelAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, policy retryPolicy) (string, error) {
+	value, err := getAttributeDo(socketKey, instanceTag, attribute, indices, "number", policy)
+	if err != nil {
+		return value, err
+	}
+	return `"` + unTransformVolume(value) + `"`, nil
+}
+
+// Checks that a volume is a number from 0-100. Returns the volume without quotes.
+func validateVolume(volume string) (string, error) {
+	volume = strings.Trim(volume, "\"")
+	number, err := strconv.ParseFloat(volume, 64)
+	if err != nil || math.IsNaN(number) || number < 0 || number > 100 {
+		return volume, fmt.Errorf("%w: volume must be a number from 0 to 100, got %s", errInvalidArgument, volume)
+	}
+	return volume, nil
+}
+
+// Sets a level attribute of the specified instance tag. Takes a value from 0-100.
+func setLevelAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, volume string, policy retryPolicy) (string, error) {
+	volume, err := validateVolume(volume)
+	if err != nil {
+		return "notok", err
+	}
+	transformedVol := transformVolume(volume)
+	framework.Log("Transformed Volume: " + transformedVol)
+	return setAttributeDo(socketKey, instanceTag, attribute, indices, transformedVol, policy)
+}
+
+// Gets a true/false attribute of the specified instance tag.
+func getStateAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, policy retryPolicy) (string, error) {
+	value, err := getAttributeDo(socketKey, instanceTag, attribute, indices, "state", policy)
+	if err != nil {
+		return value, err
+	}
+	return `"` + value + `"`, nil
+}
+
+// Sets a true/false attribute of the specified instance tag.
+func setStateAttributeDo(socketKey string, instanceTag string, attribute string, indices []string, state string, policy retryPolicy) (string, error) {
+	state = strings.Trim(state, "\"")
+	if state != "true" && state != "false" {
+		return "notok", fmt.Errorf("%w: %s must be true or false, got %s", errInvalidArgument, attribute, state)
+	}
+	return setAttributeDo(socketKey, instanceTag, attribute, indices, state, policy)
+}
+
+// Reads several attributes of the specified instance tag and returns them as one JSON object keyed by
+// attribute name, with numbers and true/false as JSON values, e.g. {"bypass":false,"threshold":-20}.
+// Attributes the DSP rejects are left out since not every block of a family has every attribute.
+func getAttributesObjectDo(socketKey string, instanceTag string, attributes []blockAttribute, indices []string, policy retryPolicy) (string, error) {
+	function := "getAttributesObjectDo"
+
+	values := make(map[string]any)
+	for _, attribute := range attributes {
+		value, err := getAttributeDo(socketKey, instanceTag, attribute.name, indices, attribute.valueType, policy)
+		if commandRejected(err) {
+			continue
+		}
+		if err != nil {
+			return value, err
+		}
+
+		switch attribute.valueType {
+		case "number":
+			values[attribute.name], _ = strconv.ParseFloat(value, 64)
+		case "state":
+			values[attribute.name] = value == "true"
+		default:
+			values[attribute.name] = value
+		}
+	}
+
+	if len(values) == 0 {
+		errMsg := function + " - 7cmq1wz - " + instanceTag + " didn't accept any of the attributes asked for"
+		return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
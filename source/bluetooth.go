@@ -0,0 +1,41 @@
+package main
+
+// EX-UBT expanders show up as Bluetooth blocks. Pairing state lives on the expander, so stale pairings
+// can be cleared from here without anyone visiting the room.
+
+// Gets the state of a Bluetooth block as a JSON object, e.g.
+// {"discoverable":false,"connected":true,"connectedDeviceName":"Jo's phone","streaming":true}.
+// connected is subscribed to, so phones connecting and disconnecting show up in GET events.
+func getBluetooth(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getBluetooth", "bluetooth", func(policy retryPolicy) (string, error) {
+		_, err := getSubscribedStateDo(socketKey, instanceTag, "connected", "", policy)
+		if err != nil && !commandRejected(err) {
+			return `"unknown"`, err
+		}
+		return getAttributesObjectDo(socketKey, instanceTag, blockRegistry["Bluetooth"].attributes, nil, policy)
+	})
+}
+
+// Returns true if the Bluetooth block is discoverable for pairing.
+func getBluetoothDiscoverable(socketKey string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getBluetoothDiscoverable", "bluetoothdiscoverable", "Bluetooth", "discoverable", instanceTag)
+}
+
+// Makes the Bluetooth block discoverable for pairing (true) or hides it (false).
+func setBluetoothDiscoverable(socketKey string, instanceTag string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setBluetoothDiscoverable", "bluetoothdiscoverable", "Bluetooth", "discoverable", instanceTag, state)
+}
+
+// Disconnects the device currently connected to the Bluetooth block. Pairings are kept.
+func setBluetoothDisconnect(socketKey string, instanceTag string) (string, error) {
+	return runSet(socketKey, "setBluetoothDisconnect", "bluetoothdisconnect", func(policy retryPolicy) (string, error) {
+		return serviceDo(socketKey, instanceTag, "disconnect", nil, policy)
+	})
+}
+
+// Forgets every device paired with the Bluetooth block, disconnecting the current one.
+func setBluetoothClearPairings(socketKey string, instanceTag string) (string, error) {
+	return runSet(socketKey, "setBluetoothClearPairings", "bluetoothclearpairings", func(policy retryPolicy) (string, error) {
+		return serviceDo(socketKey, instanceTag, "clearPairedDevices", nil, policy)
+	})
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes blocks use to report how many channels, sources, inputs, outputs, rooms, walls or zones they have.
+var countAttributes = []string{"numChannels", "numSources", "numInputs", "numOutputs", "numBands", "numLines", "numRooms", "numWalls", "numZones"}
+
+// Gets the channel counts of the specified instance tag as a JSON object, e.g. {"numInputs":8,"numOutputs":4},
+// so panels don't have to hardcode channel numbers. Counts are cached until the connection to the DSP is reopened.
+func getChannels(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getChannels", "channels", func(policy retryPolicy) (string, error) {
+		return getChannelsDo(socketKey, instanceTag, policy)
+	})
+}
+
+// Reads every count attribute the block has.
+func getChannelsDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
+	function := "getChannelsDo"
+
+	counts := make(map[string]int)
+	for _, attribute := range countAttributes {
+		count, err := getCountDo(socketKey, instanceTag, attribute, policy)
+		if commandRejected(err) {
+			continue
+		}
+		if err != nil {
+			return `"unknown"`, err
+		}
+		counts[attribute] = count
+	}
+
+	if len(counts) == 0 {
+		errMsg := function + " - 6wla0vd - " + instanceTag + " doesn't report any channel counts"
+		return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
+	encoded, err := json.Marshal(counts)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// Returns a count attribute (e.g. numChannels) of the specified instance tag, from the cache if it has been read before.
+// A block without the attribute gives an error for which commandRejected is true. Rejections aren't cached.
+func getCountDo(socketKey string, instanceTag string, attribute string, policy retryPolicy) (int, error) {
+	blockMetadataMutex.Lock()
+	count, found := blockMetadataCache[socketKey][instanceTag].counts[attribute]
+	blockMetadataMutex.Unlock()
+	if found {
+		return count, nil
+	}
+
+	value, err := getAttributeDo(socketKey, instanceTag, attribute, nil, "number", policy)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	count = int(number)
+
+	updateBlockMetadata(socketKey, instanceTag, func(metadata *blockMetadata) {
+		if metadata.counts == nil {
+			metadata.counts = make(map[string]int)
+		}
+		metadata.counts[attribute] = count
+	})
+
+	return count, nil
+}
+
+// The count attribute that bounds each index of an attribute, where it isn't the block's channel, band or input count.
+var indexCounts = map[string][]string{
+	"crosspointLevel":      {"numInputs", "numOutputs"},
+	"crosspointLevelState": {"numInputs", "numOutputs"},
+	"input":                {"numOutputs"}, // a router selects an input for each output
+	"inputLevel":           {"numInputs"},
+	"inputMute":            {"numInputs"},
+	"inputLabel":           {"numInputs"},
+	"crosspoint":           {"numInputs"},
+	"outputLevel":          {"numOutputs"},
+	"outputMute":           {"numOutputs"},
+	"outputLabel":          {"numOutputs"},
+	"wallState":            {"numWalls"},
+	"group":                {"numRooms"},
+	"sourceSelection":      {"numRooms"},
+	"levelOut":             {"numRooms"},
+	"muteOut":              {"numRooms"},
+	"roomLabel":            {"numRooms"},
+	"zoneEnable":           {"numZones"},
+	"autoAnswer":           {"numLines"},
+	"dndEnable":            {"numLines"},
+	"displayNameLabel":     {"numLines"},
+}
+
+// Checks indices against the block's counts once they have been discovered, so a channel the block doesn't have
+// is refused without asking the DSP. Nothing is checked for counts that haven't been read yet.
+func checkIndexCounts(socketKey string, instanceTag string, attribute string, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	blockMetadataMutex.Lock()
+	counts := maps.Clone(blockMetadataCache[socketKey][instanceTag].counts)
+	blockMetadataMutex.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	countAttributes, found := indexCounts[attribute]
+	if !found {
+		// Blocks with inputs and outputs, like routers, index other attributes by either one, so those aren't checked
+		_, inputs := counts["numInputs"]
+		_, outputs := counts["numOutputs"]
+		if inputs && outputs {
+			return nil
+		}
+		// Other indexed attributes are numbered by the block's channels, bands or inputs, and a block only has one of these
+		for _, countAttribute := range []string{"numChannels", "numBands", "numInputs"} {
+			_, cached := counts[countAttribute]
+			if cached {
+				countAttributes = []string{countAttribute}
+				break
+			}
+		}
+	}
+
+	for i, countAttribute := range countAttributes {
+		count, cached := counts[countAttribute]
+		if !cached || i >= len(indices) {
+			continue
+		}
+		index, err := strconv.Atoi(indices[i])
+		if err == nil && index > count {
+			return fmt.Errorf("%w: %s only has %d (%s), got index %d", errInvalidArgument, instanceTag, count, countAttribute, index)
+		}
+	}
+	return nil
+}
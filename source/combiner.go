@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Room Combiner blocks join rooms into groups as the walls between them open and close. The DSP regroups
+// rooms itself when a wall changes, so a UI only needs the walls and the groups to show the current layout.
+// Walls and groups are subscribed to when read, so every regrouping shows up in GET events.
+
+// Gets the wall states of a room combiner as a JSON object keyed by wall, e.g. {"1":"open","2":"closed"}.
+func getCombinerWalls(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getCombinerWalls", "combinerwalls", func(policy retryPolicy) (string, error) {
+		return getCombinerIndexedDo(socketKey, instanceTag, "wallState", "numWalls", "state", policy)
+	})
+}
+
+// Gets the group each room of a room combiner is in as a JSON object keyed by room, e.g. {"1":1,"2":1,"3":2}.
+// Rooms in the same group are combined. Group 0 means the room isn't in any group.
+func getCombinerGroups(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getCombinerGroups", "combinergroups", func(policy retryPolicy) (string, error) {
+		return getCombinerIndexedDo(socketKey, instanceTag, "group", "numRooms", "number", policy)
+	})
+}
+
+// Subscribes to and reads an attribute for every room or wall of the combiner.
+func getCombinerIndexedDo(socketKey string, instanceTag string, attribute string, countAttribute string, respType string, policy retryPolicy) (string, error) {
+	function := "getCombinerIndexedDo"
+
+	count, err := getCountDo(socketKey, instanceTag, countAttribute, policy)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	values := make(map[string]any)
+	for i := 1; i <= count; i++ {
+		index := strconv.Itoa(i)
+		value, err := getSubscribedAttributeDo(socketKey, instanceTag, attribute, index, respType, policy)
+		if err != nil {
+			return `"unknown"`, err
+		}
+		if respType == "state" {
+			values[index] = wallPosition(value)
+		} else {
+			values[index], _ = strconv.Atoi(formatNumber(value))
+		}
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// Returns open or closed for a wallState value. Tesira reports a closed wall as true.
+func wallPosition(state string) string {
+	if state == "true" {
+		return "closed"
+	}
+	return "open"
+}
+
+// Gets the source selected for a room of the combiner. 0 means no source.
+func getCombinerSource(socketKey string, instanceTag string, room string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getCombinerSource", "combinersource", "Room Combiner", "sourceSelection", instanceTag, room)
+}
+
+// Gets the output level of a room of the combiner in dB.
+func getCombinerLevel(socketKey string, instanceTag string, room string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getCombinerLevel", "combinerlevel", "Room Combiner", "levelOut", instanceTag, room)
+}
+
+// Opens or closes a wall of the combiner. Takes open or closed.
+func setCombinerWall(socketKey string, instanceTag string, wall string, position string) (string, error) {
+	function := "setCombinerWall"
+
+	var state string
+	switch strings.Trim(position, "\"") {
+	case "open":
+		state = "false"
+	case "closed":
+		state = "true"
+	default:
+		errMsg := function + " - 2mfe8rt - wall must be open or closed, got " + position
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return setRegisteredAttribute(socketKey, function, "combinerwall", "Room Combiner", "wallState", instanceTag, state, wall)
+}
+
+// Assigns a room of the combiner to a group. 0 takes the room out of every group.
+// There can't be more groups than rooms.
+func setCombinerGroup(socketKey string, instanceTag string, room string, group string) (string, error) {
+	return setCombinerNumber(socketKey, "setCombinerGroup", "combinergroup", "group", "numRooms", instanceTag, room, group)
+}
+
+// Selects the source for a room of the combiner. 0 selects no source.
+func setCombinerSource(socketKey string, instanceTag string, room string, source string) (string, error) {
+	return setCombinerNumber(socketKey, "setCombinerSource", "combinersource", "sourceSelection", "numSources", instanceTag, room, source)
+}
+
+// Sets a room's group or source, which is a whole number from 0 up to one of the block's counts.
+// The upper limit is only checked if the block reports the count.
+func setCombinerNumber(socketKey string, function string, setting string, attribute string, countAttribute string, instanceTag string, room string, value string) (string, error) {
+	value = strings.Trim(value, "\"")
+	number, err := strconv.Atoi(value)
+	if err == nil {
+		err = validateIndices([]string{room})
+	}
+	if err != nil || number < 0 {
+		errMsg := function + " - 8kpw4tz - " + attribute + " must be a whole number from 0 for a room from 1, got " + value + " for room " + room
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, setting, func(policy retryPolicy) (string, error) {
+		count, err := getCountDo(socketKey, instanceTag, countAttribute, policy)
+		if err != nil && !commandRejected(err) {
+			return "notok", err
+		}
+		if err == nil && number > count {
+			return "notok", fmt.Errorf("%w: %s only has %d (%s), got %s %d", errInvalidArgument, instanceTag, count, countAttribute, attribute, number)
+		}
+		return setAttributeDo(socketKey, instanceTag, attribute, []string{room}, value, policy)
+	})
+}
+
+// Sets the output level of a room of the combiner in dB.
+func setCombinerLevel(socketKey string, instanceTag string, room string, level string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setCombinerLevel", "combinerlevel", "Room Combiner", "levelOut", instanceTag, level, room)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Command String blocks send configured strings out a serial port or over IP, e.g. to turn a projector on.
+// Triggering one here sends the string the same way the DSP's own logic would.
+
+// Stops reading command strings of a block that never rejects an index.
+const maxCommandStrings = 64
+
+// Gets the configured strings of a command string block as a JSON object keyed by index, e.g. {"1":"PWR ON\r","2":"PWR OFF\r"}.
+func getCommandStrings(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getCommandStrings", "commandstrings", func(policy retryPolicy) (string, error) {
+		return getCommandStringsDo(socketKey, instanceTag, policy)
+	})
+}
+
+// Reads the command of each index until the DSP rejects one.
+func getCommandStringsDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
+	function := "getCommandStringsDo"
+
+	commands := make(map[string]string)
+	for i := 1; i <= maxCommandStrings; i++ {
+		index := strconv.Itoa(i)
+		command, err := getAttributeDo(socketKey, instanceTag, "command", []string{index}, "string", policy)
+		if commandRejected(err) {
+			break
+		}
+		if err != nil {
+			return `"unknown"`, err
+		}
+		commands[index] = command
+	}
+
+	if len(commands) == 0 {
+		errMsg := function + " - 5jxr0cw - " + instanceTag + " has no command strings"
+		return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
+	encoded, err := json.Marshal(commands)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// Sends the command string at the given index.
+func setCommandString(socketKey string, instanceTag string, index string) (string, error) {
+	function := "setCommandString"
+
+	index = strings.Trim(index, "\"")
+	err := validateIndices([]string{index})
+	if err != nil {
+		errMsg := function + " - 9wqk3nd - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	// A device may act on a command twice, e.g. toggling power back off, so it's only sent once
+	err = connect(socketKey, function)
+	if err != nil {
+		return "notok", err
+	}
+	_, err = serviceDo(socketKey, instanceTag, "sendCommand", []string{index}, retryPolicyFor("commandstring"))
+	if err != nil {
+		framework.AddToErrors(socketKey, function+" - 7rdv2pk - "+err.Error())
+		return "notok", err
+	}
+	return "ok", nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Speed of sound used to turn a distance into a delay, in meters per millisecond (343 m/s at 20°C).
+const speedOfSound = 0.343
+
+const metersPerFoot = 0.3048
+
+// Gets the delay of a channel of a Delay block in milliseconds.
+func getDelay(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getDelay", "delay", "Delay", "delay", instanceTag, channel)
+}
+
+// Returns true if the delay channel is bypassed.
+func getDelayBypass(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getDelayBypass", "delaybypass", "Delay", "bypass", instanceTag, channel)
+}
+
+// Sets the delay of a channel of a Delay block. The value is in milliseconds, or a distance to the delay
+// speakers ending in ft or m (e.g. "40ft" or "12.5m") which is converted using the speed of sound.
+// The delay is checked against the block's maximum delay before it is set.
+func setDelay(socketKey string, instanceTag string, channel string, delay string) (string, error) {
+	function := "setDelay"
+
+	milliseconds, err := parseDelay(strings.Trim(delay, "\""))
+	if err == nil {
+		err = validateIndices([]string{channel})
+	}
+	if err != nil {
+		errMsg := function + " - 3nfy6ka - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, "delay", func(policy retryPolicy) (string, error) {
+		return setDelayDo(socketKey, instanceTag, channel, milliseconds, policy)
+	})
+}
+
+// Checks the delay against the block's maximum delay and sets it.
+func setDelayDo(socketKey string, instanceTag string, channel string, milliseconds float64, policy retryPolicy) (string, error) {
+	function := "setDelayDo"
+
+	maxDelay, err := getAttributeDo(socketKey, instanceTag, "maxDelay", nil, "number", policy)
+	if err != nil && !commandRejected(err) {
+		return maxDelay, err
+	}
+	if err == nil {
+		max, _ := strconv.ParseFloat(maxDelay, 64)
+		if milliseconds > max {
+			errMsg := fmt.Sprintf(function+" - 9aqd2mr - %v ms is longer than the maximum delay of %v ms", milliseconds, formatNumber(maxDelay))
+			return "notok", fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+		}
+	}
+
+	value := strconv.FormatFloat(milliseconds, 'f', 2, 64)
+	return setAttributeDo(socketKey, instanceTag, "delay", []string{channel}, value, policy)
+}
+
+// Bypasses a delay channel (true) or puts it back in circuit (false).
+func setDelayBypass(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setDelayBypass", "delaybypass", "Delay", "bypass", instanceTag, state, channel)
+}
+
+// Turns a delay such as "12.5", "12.5ms", "40ft" or "12m" into milliseconds.
+func parseDelay(delay string) (float64, error) {
+	delay = strings.ToLower(strings.TrimSpace(delay))
+
+	unit := "ms"
+	for _, suffix := range []string{"ms", "ft", "m"} {
+		if strings.HasSuffix(delay, suffix) {
+			unit = suffix
+			delay = strings.TrimSpace(strings.TrimSuffix(delay, suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(delay, 64)
+	if err != nil || value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("delay must be zero or a positive number of ms, ft or m, got " + delay)
+	}
+
+	switch unit {
+	case "ft":
+		return value * metersPerFoot / speedOfSound, nil
+	case "m":
+		return value / speedOfSound, nil
+	}
+	return value, nil
+}
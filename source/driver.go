@@ -2,9 +2,12 @@ package main
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -88,7 +91,14 @@ func loginNegotiation(socketKey string) bool {
 }
 
 // Sends a command and checks that the response is valid. Otherwise, tries reading again.
-func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, respType string) (string, error) {
+// The policy decides how many reads to make and how long to keep reading.
+func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, respType string, policy retryPolicy) (string, error) {
+	// Only the final CR may end the command. One anywhere else would send a second command the caller never checked.
+	if strings.ContainsAny(strings.TrimSuffix(cmdStr, "\r"), "\r\n") {
+		errMsg := "x7eq2bm - command contains a line break: " + strconv.Quote(cmdStr)
+		return "unknown", fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
 	// Send the command. Return if there is an error.
 	sent := convertAndSend(socketKey, cmdStr)
 	if !sent {
@@ -96,19 +106,37 @@ func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, re
 		return "unknown", errors.New(errMsg)
 	}
 
-	// Try to read at most 5 times if the response is not what is expected.
+	// Try to read at most policy.readAttempts times if the response is not what is expected.
 	// The DSP might respond with an echo or a response for a different command.
-	maxRetries := 5
+	maxRetries := policy.readAttempts
+	deadline := time.Now().Add(policy.readDeadline)
+	publishes := 0
 	validResponse := false
 	var resp string
 	var err error
 
 	for maxRetries > 0 {
+		// A read that is already in progress can't be interrupted, so the deadline is checked between reads
+		if policy.readDeadline > 0 && (maxRetries < policy.readAttempts || publishes > 0) && time.Now().After(deadline) {
+			framework.Log("Read deadline passed. Giving up on the response")
+			break
+		}
 		resp, err = readAndConvert(socketKey)
 		if err != nil {
 			return resp, err
 		}
 
+		// Subscribed attributes are published in between responses. These don't use up read attempts,
+		// unless there are so many that the response we want might never come.
+		if strings.HasPrefix(resp, "! ") {
+			recordPublish(socketKey, resp)
+			publishes++
+			if publishes > 100 {
+				maxRetries--
+			}
+			continue
+		}
+
 		// Checking if the response is an echo of the sent command or an error
 		if strings.TrimSpace(resp) == strings.TrimSpace(cmdStr) {
 			framework.Log("Got an echo. Reading again")
@@ -123,7 +151,21 @@ func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, re
 
 		// Checking that the response matches what is expected for the cmdType and respType
 		// For example, a volume query should return a number and a command should return +OK.
-		if cmdType == "query" {
+		if cmdType == "query" && respType == "list" {
+			// Valid if the response carries a list, e.g. +OK "list":["Level1" "Mute1"]
+			_, list, found := strings.Cut(resp, "\"list\":")
+			if found && strings.HasPrefix(list, "[") {
+				resp = list
+				validResponse = true
+				break
+			}
+		} else if cmdType == "query" && respType == "any" {
+			// Valid as long as the DSP acknowledged the query
+			if strings.HasPrefix(resp, "+OK") {
+				validResponse = true
+				break
+			}
+		} else if cmdType == "query" {
 			_, value, found := strings.Cut(resp, "\"value\":")
 			if found {
 				if respType == "number" {
@@ -141,6 +183,20 @@ func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, re
 						validResponse = true
 						break
 					}
+				} else if respType == "string" {
+					// Valid if the response is a quoted string, e.g. "value":"Mic 1"
+					if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+						resp = value[1 : len(value)-1]
+						validResponse = true
+						break
+					}
+				} else if respType == "enum" {
+					// Valid if the response is a bare word, e.g. "value":MEDIUM
+					if value != "" && !strings.ContainsAny(value, "\" ") {
+						resp = value
+						validResponse = true
+						break
+					}
 				}
 			}
 		} else if cmdType == "command" {
@@ -158,12 +214,22 @@ func sendAndValidateResponse(socketKey string, cmdStr string, cmdType string, re
 	if validResponse {
 		return resp, err
 	} else {
-		errMsg := "tried to read 5 times. no valid response from the biamp"
+		errMsg := fmt.Sprintf("tried to read %d times. no valid response from the biamp", policy.readAttempts-maxRetries)
 		err = errors.New(errMsg)
 		return "unknown", err
 	}
 }
 
+// Returns true if the error is the DSP answering -ERR, e.g. because a block doesn't have the attribute asked for.
+func commandRejected(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Read error")
+}
+
+// Returns true if the DSP rejected a command because no block has the instance tag, e.g. -ERR address not found: {...}.
+func addressNotFound(err error) bool {
+	return commandRejected(err) && strings.Contains(strings.ToLower(err.Error()), "address not found")
+}
+
 // Takes value from the range 0-100 and transforms it to the range the Biamp uses (-100 - +12).
 func transformVolume(vol string) string {
 	function := "transformVolume"
@@ -208,46 +274,18 @@ func unTransformVolume(vol string) string {
 // GET Functions
 
 func getVolume(socketKey string, instanceTag string, channel string) (string, error) {
-	function := "getVolume"
-
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = getVolumeDo(socketKey, instanceTag, channel)
-		if value == `"unknown"` { // Something went wrong - perhaps try again
-			framework.Log(function + " - fq3sdvc - retrying volume operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + "f839dk4 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runGet(socketKey, "getVolume", "volume", func(policy retryPolicy) (string, error) {
+		return getVolumeDo(socketKey, instanceTag, channel, policy)
+	})
 }
 
 // Gets the volume level of the specified instance tag and channel. Returns a value between 0 and 100.
-func getVolumeDo(socketKey string, instanceTag string, channel string) (string, error) {
+func getVolumeDo(socketKey string, instanceTag string, channel string, policy retryPolicy) (string, error) {
 	function := "getVolumeDo"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	cmdString := instanceTag + " get level " + channel + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "number")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "number", policy)
 
 	if err != nil {
 		return value, err
@@ -262,46 +300,18 @@ func getVolumeDo(socketKey string, instanceTag string, channel string) (string,
 }
 
 func getGain(socketKey string, instanceTag string) (string, error) {
-	function := "getGain"
-
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = getGainDo(socketKey, instanceTag)
-		if value == `"unknown"` { // Something went wrong - perhaps try again
-			framework.Log(function + " - fq3sdvc - retrying gain operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + "f839dk4 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runGet(socketKey, "getGain", "gain", func(policy retryPolicy) (string, error) {
+		return getGainDo(socketKey, instanceTag, policy)
+	})
 }
 
 // Gets the gain level of the specified instance tag. Returns a value between 0 and 100.
-func getGainDo(socketKey string, instanceTag string) (string, error) {
+func getGainDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
 	function := "getGainDo"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	cmdString := instanceTag + " get gain" + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "number")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "number", policy)
 
 	if err != nil {
 		return value, err
@@ -317,50 +327,16 @@ func getGainDo(socketKey string, instanceTag string) (string, error) {
 
 // Returns true if the channel is muted. False if it is not muted.
 func getAudioMute(socketKey string, instanceTag string, channel string) (string, error) {
-	function := "getAudioMute"
-
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = getMuteToggleDo(socketKey, instanceTag, channel)
-		if value == `"unknown"` { // Something went wrong - perhaps try again
-			framework.Log(function + " - sdf09nd - retrying audiomute operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + "f4fk5n3 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runGet(socketKey, "getAudioMute", "audiomute", func(policy retryPolicy) (string, error) {
+		return getMuteToggleDo(socketKey, instanceTag, channel, policy)
+	})
 }
 
 // Returns true if Voice Lift is on, false if Voice Lift is off
 func getVoiceLift(socketKey string, instanceTag string, channel string) (string, error) {
-	function := "getVoiceLift"
-
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = getMuteToggleDo(socketKey, instanceTag, channel)
-		if value == `"unknown"` { // Something went wrong - perhaps try again
-			framework.Log(function + " - sdf09nd - retrying voice lift operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + "f4fk5n3 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
+	value, err := runGet(socketKey, "getVoiceLift", "voicelift", func(policy retryPolicy) (string, error) {
+		return getMuteToggleDo(socketKey, instanceTag, channel, policy)
+	})
 
 	//If Voice Lift mute is true, Voice Lift is "off". If mute is false, Voice Lift is "on"
 	if value == "\"true\"" {
@@ -373,22 +349,12 @@ func getVoiceLift(socketKey string, instanceTag string, channel string) (string,
 }
 
 // Gets the mute status of the specified instance tag and channel. Returns true or false.
-func getMuteToggleDo(socketKey string, instanceTag string, channel string) (string, error) {
+func getMuteToggleDo(socketKey string, instanceTag string, channel string, policy retryPolicy) (string, error) {
 	function := "getMuteToggleDo"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	cmdString := instanceTag + " get mute " + channel + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "state")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "state", policy)
 
 	if err != nil {
 		return value, err
@@ -402,93 +368,54 @@ func getMuteToggleDo(socketKey string, instanceTag string, channel string) (stri
 
 // Returns true if Logic Selector is true, false if Logic Selector is false
 func getLogicSelector(socketKey string, instanceTag string, channel string) (string, error) {
-	function := "getLogicSelector"
-
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = getStateToggleDo(socketKey, instanceTag, channel)
-		if value == `"unknown"` { // Something went wrong - perhaps try again
-			framework.Log(function + " - 94ndk3l - retrying getLogicSelector operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + "aoi5pj2 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runGet(socketKey, "getLogicSelector", "logicselector", func(policy retryPolicy) (string, error) {
+		return getStateToggleDo(socketKey, instanceTag, channel, policy)
+	})
 }
 
 // Returns the channel number that is set to true
 func getAudioMode(socketKey string, instanceTag string) (string, error) {
-	function := "getAudioMode"
+	return runGet(socketKey, "getAudioMode", "audiomode", func(policy retryPolicy) (string, error) {
+		return getAudioModeDo(socketKey, instanceTag, policy)
+	})
+}
 
-	value := `"unknown"`
-	err := error(nil)
-	maxRetries := 2
-	channel := 1
-	for maxRetries > 0 {
-		channel = 1
-		// Loop through 5 channels to find which is set to true
-		for channel <= 5 {
-			value, err = getStateToggleDo(socketKey, instanceTag, strconv.Itoa(channel))
+// Loops through the channels of the specified instance tag to find which is set to true.
+// Older Logic State blocks that don't report numChannels are assumed to have 5 channels.
+func getAudioModeDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
+	function := "getAudioModeDo"
 
-			if err != nil { // Something went wrong - perhaps try again
-				framework.Log(function + " - 54ijxl - retrying getAudioMode operation")
-				maxRetries--
-				time.Sleep(1 * time.Second)
+	numChannels, err := getCountDo(socketKey, instanceTag, "numChannels", policy)
+	if commandRejected(err) {
+		numChannels = 5
+	} else if err != nil {
+		return `"unknown"`, err
+	}
 
-				if maxRetries == 0 {
-					errMsg := fmt.Sprintf(function + "2jj3hx - max retries reached")
-					framework.AddToErrors(socketKey, errMsg)
-					break
-				}
-			} else { // Got a response
-				// If one channel is true, return
-				if value == "\"true\"" {
-					maxRetries = 0
-					break
-				} else if value == "\"false\"" {
-					channel++
-					if channel >= 6 {
-						maxRetries--
-					}
-				}
-			}
+	for channel := 1; channel <= numChannels; channel++ {
+		value, err := getStateToggleDo(socketKey, instanceTag, strconv.Itoa(channel), policy)
+		if err != nil {
+			return value, err
 		}
-	}
 
-	// If the for loop broke with a channel returning true, return the channel number
-	if value == "\"true\"" {
-		return `"` + strconv.Itoa(channel) + `"`, err
-	} else {
-		return `"unknown"`, err
+		// If one channel is true, return the channel number
+		if value == "\"true\"" {
+			return `"` + strconv.Itoa(channel) + `"`, nil
+		}
 	}
+
+	// No channel being set is a valid state of the block, not a failure to retry
+	framework.Log(function + " - 2jj3hx - no channel is set to true")
+	return `"unknown"`, nil
 }
 
 // Gets the state of the specified instance tag and channel. Returns true or false.
-func getStateToggleDo(socketKey string, instanceTag string, channel string) (string, error) {
+func getStateToggleDo(socketKey string, instanceTag string, channel string, policy retryPolicy) (string, error) {
 	function := "getStateToggleDo"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - jl3kldj - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	cmdString := instanceTag + " get state " + channel + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "state")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "state", policy)
 
 	if err != nil {
 		return value, err
@@ -500,53 +427,156 @@ func getStateToggleDo(socketKey string, instanceTag string, channel string) (str
 	return `"` + value + `"`, nil
 }
 
-//SET Functions
+// Returns the instance tags (aliases) in the running configuration as a JSON list.
+// If probe is "probe", returns a JSON object mapping each alias to its best guess block type instead.
+func getAliases(socketKey string, probe string) (string, error) {
+	return runGet(socketKey, "getAliases", "aliases", func(policy retryPolicy) (string, error) {
+		return getAliasesDo(socketKey, probe, policy)
+	})
+}
 
-func setVolume(socketKey string, instanceTag string, channel string, volume string) (string, error) {
-	function := "setVolume"
+// Gets the list of aliases from the DSP session and optionally probes each one for its block type.
+func getAliasesDo(socketKey string, probe string, policy retryPolicy) (string, error) {
+	function := "getAliasesDo"
 
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setVolumeDo(socketKey, instanceTag, channel, volume)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - fq3sdvc - retrying volume operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - fds3nf3 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
+	cmdString := "SESSION get aliases\r"
+
+	value, err := sendAndValidateResponse(socketKey, cmdString, "query", "list", policy)
+
+	if err != nil {
+		return value, err
+	}
+
+	aliases := parseList(value)
+	framework.Log(function + " - Decoded Response: " + strings.Join(aliases, ", "))
+
+	var encoded []byte
+	if probe == "probe" {
+		blockTypes := make(map[string]string)
+		for _, alias := range aliases {
+			blockTypes[alias] = probeBlockType(socketKey, alias, policy)
+			setBlockType(socketKey, alias, blockTypes[alias])
+		}
+		encoded, err = json.Marshal(blockTypes)
+	} else {
+		encoded, err = json.Marshal(aliases)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf(function+" - 2mvq8ro - error encoding aliases: %v", err.Error())
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	// If we got here, the response was good, so successful return with the aliases
+	return string(encoded), nil
+}
+
+// Returns the best guess block type for an alias, or "unknown" if the probes can't tell the registered types apart.
+// TTP has no command that reports a block's type, so attributes are queried to rule out block types until one is left.
+func probeBlockType(socketKey string, alias string, policy retryPolicy) string {
+	if alias == "DEVICE" {
+		return "Device"
+	}
+	// Aliases with spaces or quotes can't be sent unquoted, and every query would fail as if the attribute were missing
+	if checkCommandParts(alias, "get", nil) != nil {
+		return "unknown"
+	}
+
+	candidates := make([]string, 0, len(blockRegistry))
+	for blockName := range blockRegistry {
+		candidates = append(candidates, blockName)
+	}
+	sort.Strings(candidates)
+
+	for len(candidates) > 1 {
+		query, found := nextBlockTypeProbe(candidates)
+		if !found {
+			// The remaining block types have the same attributes, e.g. Dante Input and Dante Output
+			return "unknown"
+		}
+		_, err := sendAndValidateResponse(socketKey, alias+" get "+query+"\r", "query", "any", policy)
+		// An -ERR usually means the block doesn't have this attribute. Anything else is a real failure,
+		// as is the DSP not finding the block at all, which says nothing about its type.
+		if err != nil && (!commandRejected(err) || addressNotFound(err)) {
+			return "unknown"
+		}
+		accepted := err == nil
+
+		remaining := candidates[:0]
+		for _, blockName := range candidates {
+			if slices.Contains(blockTypeQueries(blockName), query) == accepted {
+				remaining = append(remaining, blockName)
 			}
-		} else { // Succeeded
-			maxRetries = 0
 		}
+		candidates = remaining
 	}
 
-	return value, err
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return "unknown"
 }
 
-// Sets the volume for the specified instance tag and channel. Takes a value from 0-100.
-func setVolumeDo(socketKey string, instanceTag string, channel string, volume string) (string, error) {
-	function := "setVolumeDo"
-	volume = strings.Trim(volume, "\"")
+// Picks the query that splits the candidate block types most evenly, so as few queries as possible are sent.
+// Returns false if every candidate answers the same queries.
+func nextBlockTypeProbe(candidates []string) (string, bool) {
+	counts := make(map[string]int)
+	for _, blockName := range candidates {
+		for _, query := range blockTypeQueries(blockName) {
+			counts[query]++
+		}
+	}
+
+	best, bestSplit := "", 0
+	for query, count := range counts {
+		split := min(count, len(candidates)-count)
+		if split > bestSplit || (split == bestSplit && split > 0 && query < best) {
+			best, bestSplit = query, split
+		}
+	}
+	return best, bestSplit > 0
+}
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
+// Returns the get queries a registered block type answers, e.g. "level 1" or "gain", using index 1 for every index.
+func blockTypeQueries(blockName string) []string {
+	var queries []string
+	for _, attribute := range blockRegistry[blockName].attributes {
+		if attribute.readable {
+			queries = append(queries, attribute.name+strings.Repeat(" 1", attribute.indices))
 		}
 	}
+	return queries
+}
+
+// Splits a TTP list such as ["Level1" "Mute1"] into its quoted elements.
+func parseList(list string) []string {
+	items := []string{}
+	parts := strings.Split(strings.Trim(strings.TrimSpace(list), "[]"), "\"")
+	// Every odd part sits between a pair of quotes
+	for i := 1; i < len(parts); i += 2 {
+		items = append(items, parts[i])
+	}
+	return items
+}
+
+//SET Functions
+
+func setVolume(socketKey string, instanceTag string, channel string, volume string) (string, error) {
+	return runSet(socketKey, "setVolume", "volume", func(policy retryPolicy) (string, error) {
+		return setVolumeDo(socketKey, instanceTag, channel, volume, policy)
+	})
+}
+
+// Sets the volume for the specified instance tag and channel. Takes a value from 0-100.
+func setVolumeDo(socketKey string, instanceTag string, channel string, volume string, policy retryPolicy) (string, error) {
+	function := "setVolumeDo"
+	volume = strings.Trim(volume, "\"")
 
 	transformedVol := transformVolume(volume)
 	framework.Log("Transformed Volume: " + transformedVol)
 
 	cmdString := instanceTag + " set level " + channel + " " + transformedVol + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -559,50 +589,22 @@ func setVolumeDo(socketKey string, instanceTag string, channel string, volume st
 }
 
 func setGain(socketKey string, instanceTag string, gain string) (string, error) {
-	function := "setGain"
-
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setGainDo(socketKey, instanceTag, gain)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - fq3sdvc - retrying gain operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - fds3nf3 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runSet(socketKey, "setGain", "gain", func(policy retryPolicy) (string, error) {
+		return setGainDo(socketKey, instanceTag, gain, policy)
+	})
 }
 
 // Sets the gain for the specified instance tag. Takes a value from 0-100.
-func setGainDo(socketKey string, instanceTag string, gain string) (string, error) {
+func setGainDo(socketKey string, instanceTag string, gain string, policy retryPolicy) (string, error) {
 	function := "setGainDo"
 	gain = strings.Trim(gain, "\"")
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	transformedGain := transformVolume(gain)
 	framework.Log("Transformed Gain: " + transformedGain)
 
 	cmdString := instanceTag + " set gain " + transformedGain + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -616,32 +618,13 @@ func setGainDo(socketKey string, instanceTag string, gain string) (string, error
 
 // Sets mute to true or false for the specified instance tag and channel.
 func setAudioMute(socketKey string, instanceTag string, channel string, state string) (string, error) {
-	function := "setAudioMute"
-
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setMuteToggleDo(socketKey, instanceTag, channel, state)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - fq5dhs - retrying audiomute operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - 03kfl4d - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runSet(socketKey, "setAudioMute", "audiomute", func(policy retryPolicy) (string, error) {
+		return setMuteToggleDo(socketKey, instanceTag, channel, state, policy)
+	})
 }
 
 // Turns Voice Lift on or off
 func setVoiceLift(socketKey string, instanceTag string, channel string, state string) (string, error) {
-	function := "setVoiceLift"
 	state = strings.Trim(state, "\"")
 
 	// Flipping to make the GUI button make sense.
@@ -652,44 +635,19 @@ func setVoiceLift(socketKey string, instanceTag string, channel string, state st
 		state = "true"
 	}
 
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setMuteToggleDo(socketKey, instanceTag, channel, state)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - 3j3md3 - retrying voicelift operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - 5h4ne3 - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runSet(socketKey, "setVoiceLift", "voicelift", func(policy retryPolicy) (string, error) {
+		return setMuteToggleDo(socketKey, instanceTag, channel, state, policy)
+	})
 }
 
 // Sets mute to true or false for the specified instance tag and channel.
-func setMuteToggleDo(socketKey string, instanceTag string, channel string, state string) (string, error) {
+func setMuteToggleDo(socketKey string, instanceTag string, channel string, state string, policy retryPolicy) (string, error) {
 	function := "setMuteToggleDo"
 	state = strings.Trim(state, "\"")
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
 
 	cmdString := instanceTag + " set mute " + channel + " " + state + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -700,47 +658,20 @@ func setMuteToggleDo(socketKey string, instanceTag string, channel string, state
 	// If we got here, the response was good, so successful return with the state indication
 	return "ok", nil
 }
-func setPreset(socketKey string, presetID string) (string, error) {
-	function := "setPreset"
 
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setPresetDo(socketKey, presetID)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - k5kifj - retrying preset operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - sj34h - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+func setPreset(socketKey string, presetID string) (string, error) {
+	return runSet(socketKey, "setPreset", "preset", func(policy retryPolicy) (string, error) {
+		return setPresetDo(socketKey, presetID, policy)
+	})
 }
 
 // Recalls a device preset for the DSP by ID. Preset ID must be greater than 1001.
-func setPresetDo(socketKey string, presetID string) (string, error) {
+func setPresetDo(socketKey string, presetID string, policy retryPolicy) (string, error) {
 	function := "setPresetDo"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
-
 	cmdString := "DEVICE recallPreset " + presetID + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -754,72 +685,28 @@ func setPresetDo(socketKey string, presetID string) (string, error) {
 
 // Sets state to true or false for the specified instance tag and channel.
 func setLogicSelector(socketKey string, instanceTag string, channel string, state string) (string, error) {
-	function := "setLogicSelector"
-
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setStateToggleDo(socketKey, instanceTag, channel, state)
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - ir3jdd - retrying setLogicSelector operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - 9jcj8k - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runSet(socketKey, "setLogicSelector", "logicselector", func(policy retryPolicy) (string, error) {
+		return setStateToggleDo(socketKey, instanceTag, channel, state, policy)
+	})
 }
 
 // Sets state to true for the specified instance tag and channel.
 func setAudioMode(socketKey string, instanceTag string, channel string) (string, error) {
-	function := "setAudioMode"
 	channel = strings.Trim(channel, "\"")
 
-	value := "notok"
-	err := error(nil)
-	maxRetries := 2
-	for maxRetries > 0 {
-		value, err = setStateToggleDo(socketKey, instanceTag, channel, "true")
-		if value != "ok" { // Something went wrong - perhaps try again
-			framework.Log(function + " - oj5jex - retrying setAudioMode operation")
-			maxRetries--
-			time.Sleep(1 * time.Second)
-			if maxRetries == 0 {
-				errMsg := fmt.Sprintf(function + " - 5lsm3g - max retries reached")
-				framework.AddToErrors(socketKey, errMsg)
-			}
-		} else { // Succeeded
-			maxRetries = 0
-		}
-	}
-
-	return value, err
+	return runSet(socketKey, "setAudioMode", "audiomode", func(policy retryPolicy) (string, error) {
+		return setStateToggleDo(socketKey, instanceTag, channel, "true", policy)
+	})
 }
 
 // Sets state to true or false for the specified instance tag and channel.
-func setStateToggleDo(socketKey string, instanceTag string, channel string, state string) (string, error) {
+func setStateToggleDo(socketKey string, instanceTag string, channel string, state string, policy retryPolicy) (string, error) {
 	function := "setStateToggleDo"
 	state = strings.Trim(state, "\"")
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - u2nj45l - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
-	}
 
 	cmdString := instanceTag + " set state " + channel + " " + state + "\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -832,22 +719,24 @@ func setStateToggleDo(socketKey string, instanceTag string, channel string, stat
 }
 
 // Reports the health of the device.
+// Health checks are polled, so only one attempt is made. A DSP that is down is reported by the next check.
 func getHostname(socketKey string) (string, error) {
 	function := "getHostname"
 
-	connected := framework.CheckConnectionsMapExists(socketKey)
-	if !connected {
-		negotiation := loginNegotiation(socketKey)
-		if !negotiation {
-			errMsg := fmt.Sprintf(function + " - h3okxu35 - error connecting")
-			framework.AddToErrors(socketKey, errMsg)
-			return errMsg, errors.New(errMsg)
-		}
+	err := connect(socketKey, function)
+	if err != nil {
+		return `"unknown"`, err
 	}
+	return getHostnameDo(socketKey, retryPolicyFor("healthcheck"))
+}
+
+// Gets the hostname of the DSP.
+func getHostnameDo(socketKey string, policy retryPolicy) (string, error) {
+	function := "getHostnameDo"
 
 	cmdString := "DEVICE get hostname\r"
 
-	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none")
+	value, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
 
 	if err != nil {
 		return value, err
@@ -866,6 +755,9 @@ func healthCheck(socketKey string) (string, error) {
 		returnStr = "no connection"
 	} else if err != nil {
 		returnStr = "false"
+	} else if problems := networkAudioProblems(socketKey); len(problems) > 0 {
+		// The DSP answers, but audio carried over Dante or AVB is missing
+		returnStr = "false " + strings.Join(problems, "; ")
 	} else {
 		returnStr = returnStr + " hostname: " + strings.ReplaceAll(resp, `"`, "")
 	}
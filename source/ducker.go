@@ -0,0 +1,22 @@
+package main
+
+// Ducker settings, mapped to their TTP attribute. Levels are in dB.
+//
+//	GET/PUT :address/duckerlevel/:instanceTag
+var duckerSettings = map[string]string{
+	"duckerlevel":     "duckingLevel",
+	"duckerthreshold": "threshold",
+	"duckersensemute": "senseMute",
+	"duckerinputmute": "inputMute",
+	"duckerbypass":    "bypass",
+}
+
+// Gets a ducker setting of the specified instance tag.
+func getDuckerSetting(socketKey string, setting string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getDuckerSetting", setting, "Ducker", duckerSettings[setting], instanceTag)
+}
+
+// Sets a ducker setting of the specified instance tag.
+func setDuckerSetting(socketKey string, setting string, instanceTag string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setDuckerSetting", setting, "Ducker", duckerSettings[setting], instanceTag, value)
+}
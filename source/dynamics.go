@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes shared by the dynamics blocks (Compressor, Peak Limiter, Leveler and AGC).
+// Threshold and makeup gain are in dB, attack and release in milliseconds.
+var (
+	dynamicsThreshold  = blockAttribute{name: "threshold", valueType: "number", readable: true, writable: true, min: -80, max: 20}
+	dynamicsRatio      = blockAttribute{name: "ratio", valueType: "number", readable: true, writable: true, min: 1, max: 100}
+	dynamicsAttack     = blockAttribute{name: "attack", valueType: "number", readable: true, writable: true, min: 0.1, max: 200}
+	dynamicsRelease    = blockAttribute{name: "release", valueType: "number", readable: true, writable: true, min: 5, max: 5000}
+	dynamicsMakeupGain = blockAttribute{name: "makeupGain", valueType: "number", readable: true, writable: true, min: 0, max: 40}
+	dynamicsBypass     = blockAttribute{name: "bypass", valueType: "state", readable: true, writable: true}
+)
+
+// Every dynamics attribute, in the order they are read.
+var dynamicsAttributes = []blockAttribute{
+	dynamicsThreshold, dynamicsRatio, dynamicsAttack, dynamicsRelease, dynamicsMakeupGain, dynamicsBypass,
+}
+
+// Gets every dynamics setting of the specified instance tag as one JSON object, e.g.
+// {"attack":5,"bypass":false,"release":100,"threshold":-20}.
+// Settings the block doesn't have (like ratio on a Peak Limiter) are left out.
+func getDynamics(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getDynamics", "dynamics", func(policy retryPolicy) (string, error) {
+		return getAttributesObjectDo(socketKey, instanceTag, dynamicsAttributes, nil, policy)
+	})
+}
+
+// Sets one dynamics setting (threshold, ratio, attack, release, makeupGain or bypass) of the specified instance tag.
+// If aliases/probe guessed the tag's block type and that type has the setting, the value is checked against its limits.
+func setDynamics(socketKey string, instanceTag string, parameter string, value string) (string, error) {
+	function := "setDynamics"
+
+	blockTypeName := "Compressor" // has every dynamics setting
+	metadata, _ := getBlockMetadata(socketKey, instanceTag)
+	if slices.Contains([]string{"Compressor", "Peak Limiter", "Leveler", "AGC"}, metadata.blockType) {
+		_, found := blockRegistry[metadata.blockType].attribute(parameter)
+		if found {
+			blockTypeName = metadata.blockType
+		}
+	}
+
+	_, found := blockRegistry[blockTypeName].attribute(parameter)
+	if !found {
+		errMsg := function + " - p2xk8vn - " + parameter + " is not a " + blockTypeName + " setting"
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return setRegisteredAttribute(socketKey, function, "dynamics", blockTypeName, parameter, instanceTag, value)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Parametric and Graphic EQ settings. Values are in real units: gain in dB, frequency in Hz and
+// bandwidth in octaves, checked against the limits in blockRegistry before they are sent.
+//
+//	GET/PUT :address/peqgain/:instanceTag/:band
+//	GET/PUT :address/peqbypass/:instanceTag
+var eqSettings = map[string]struct {
+	blockType string
+	attribute string
+	banded    bool // the setting takes a band number after the instance tag
+}{
+	"peqgain":       {"Parametric EQ", "gain", true},
+	"peqfrequency":  {"Parametric EQ", "frequency", true},
+	"peqbandwidth":  {"Parametric EQ", "bandwidth", true},
+	"peqtype":       {"Parametric EQ", "type", true},
+	"peqbandbypass": {"Parametric EQ", "bypass", true},
+	"peqbypass":     {"Parametric EQ", "bypassAll", false},
+	"geqgain":       {"Graphic EQ", "gain", true},
+	"geqfrequency":  {"Graphic EQ", "frequency", true},
+	"geqbandbypass": {"Graphic EQ", "bypass", true},
+	"geqbypass":     {"Graphic EQ", "bypassAll", false},
+}
+
+// Gets an EQ setting for the specified instance tag and band.
+func getEQSetting(socketKey string, setting string, instanceTag string, band string) (string, error) {
+	function := "getEQSetting"
+
+	eq, found := eqSettings[setting]
+	if !found {
+		errMsg := function + " - 6bq2nxr - unrecognized EQ setting: " + setting
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	if eq.banded {
+		return getRegisteredAttribute(socketKey, function, setting, eq.blockType, eq.attribute, instanceTag, band)
+	}
+	return getRegisteredAttribute(socketKey, function, setting, eq.blockType, eq.attribute, instanceTag)
+}
+
+// Sets an EQ setting for the specified instance tag and band. Whole block settings, like peqbypass,
+// don't take a band so the value arrives in arg2 instead of arg3.
+func setEQSetting(socketKey string, setting string, instanceTag string, arg2 string, arg3 string) (string, error) {
+	function := "setEQSetting"
+
+	eq, found := eqSettings[setting]
+	if !found {
+		errMsg := function + " - 0vjd3ks - unrecognized EQ setting: " + setting
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	if eq.banded {
+		return setRegisteredAttribute(socketKey, function, setting, eq.blockType, eq.attribute, instanceTag, arg3, arg2)
+	}
+	return setRegisteredAttribute(socketKey, function, setting, eq.blockType, eq.attribute, instanceTag, arg2)
+}
@@ -0,0 +1,29 @@
+package main
+
+// Tone and Noise Generator settings, so support staff can play a test tone into a room remotely.
+// Frequencies are in Hz and levels in dB.
+//
+//	GET/PUT :address/tonefrequency/:instanceTag
+var generatorSettings = map[string]struct {
+	blockType string
+	attribute string
+}{
+	"tonefrequency": {"Tone Generator", "frequency"},
+	"tonelevel":     {"Tone Generator", "level"},
+	"tonemute":      {"Tone Generator", "mute"},
+	"noiselevel":    {"Noise Generator", "level"},
+	"noisemute":     {"Noise Generator", "mute"},
+	"noisetype":     {"Noise Generator", "type"},
+}
+
+// Gets a tone or noise generator setting of the specified instance tag.
+func getGeneratorSetting(socketKey string, setting string, instanceTag string) (string, error) {
+	generator := generatorSettings[setting]
+	return getRegisteredAttribute(socketKey, "getGeneratorSetting", setting, generator.blockType, generator.attribute, instanceTag)
+}
+
+// Sets a tone or noise generator setting of the specified instance tag.
+func setGeneratorSetting(socketKey string, setting string, instanceTag string, value string) (string, error) {
+	generator := generatorSettings[setting]
+	return setRegisteredAttribute(socketKey, "setGeneratorSetting", setting, generator.blockType, generator.attribute, instanceTag, value)
+}
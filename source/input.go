@@ -0,0 +1,23 @@
+package main
+
+// Analog input block settings. Preamp gain is in dB and moves in 6 dB steps from 0 to 66 dB,
+// unlike gain which runs the 0-100 volume curve. Level is also in dB.
+//
+//	GET/PUT :address/inputgain/:instanceTag/:channel
+var inputSettings = map[string]string{
+	"inputgain":    "gain",
+	"inputphantom": "phantomPower",
+	"inputlevel":   "level",
+	"inputmute":    "mute",
+	"inputlabel":   "label",
+}
+
+// Gets an input setting of a channel of the specified instance tag.
+func getInputSetting(socketKey string, setting string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getInputSetting", setting, "Input", inputSettings[setting], instanceTag, channel)
+}
+
+// Sets an input setting of a channel of the specified instance tag.
+func setInputSetting(socketKey string, setting string, instanceTag string, channel string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setInputSetting", setting, "Input", inputSettings[setting], instanceTag, value, channel)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Most blocks label their channels with label, while mixers and routers have separate input and output labels.
+var labelAttributes = map[string]string{
+	"":       "label",
+	"input":  "inputLabel",
+	"output": "outputLabel",
+}
+
+// The count attribute that says how many of each kind of label a block has.
+var labelCounts = map[string]string{
+	"label":       "numChannels",
+	"inputLabel":  "numInputs",
+	"outputLabel": "numOutputs",
+}
+
+// Stops reading labels of blocks that don't report a count and never reject an index.
+const maxLabelChannels = 256
+
+// Gets the channel labels of the specified instance tag as a JSON object keyed by channel, e.g. {"1":"Podium","2":"Lectern"}.
+// kind is "" for the block's channel labels, or "input" or "output" for the labels of a mixer or router.
+// If kind is "" and the block only has input labels, the input labels are returned.
+// Labels are cached until the connection to the DSP is reopened or one of them is written.
+func getLabels(socketKey string, instanceTag string, kind string) (string, error) {
+	function := "getLabels"
+
+	_, found := labelAttributes[kind]
+	if !found {
+		errMsg := function + " - 4rwc9lm - label kind must be input or output, got " + kind
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, "labels", func(policy retryPolicy) (string, error) {
+		return getLabelsDo(socketKey, instanceTag, kind, policy)
+	})
+}
+
+// Reads the labels from the cache, or from the DSP one channel at a time.
+func getLabelsDo(socketKey string, instanceTag string, kind string, policy retryPolicy) (string, error) {
+	function := "getLabelsDo"
+
+	attributes := []string{labelAttributes[kind]}
+	if kind == "" {
+		attributes = append(attributes, labelAttributes["input"])
+	}
+
+	for _, attribute := range attributes {
+		labels, found := cachedLabels(socketKey, instanceTag, attribute)
+		if !found {
+			var err error
+			labels, err = readLabelsDo(socketKey, instanceTag, attribute, policy)
+			if err != nil {
+				return `"unknown"`, err
+			}
+			updateBlockMetadata(socketKey, instanceTag, func(metadata *blockMetadata) {
+				if metadata.labels == nil {
+					metadata.labels = make(map[string]map[string]string)
+				}
+				metadata.labels[attribute] = labels
+			})
+		}
+		if len(labels) == 0 {
+			// The block doesn't have this kind of label
+			continue
+		}
+
+		encoded, err := json.Marshal(labels)
+		if err != nil {
+			return `"unknown"`, err
+		}
+		framework.Log(function + " - Decoded Response: " + string(encoded))
+		return string(encoded), nil
+	}
+
+	errMsg := fmt.Sprintf(function+" - 0sk3rbn - %s has no %s labels", instanceTag, attributes[0])
+	return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+}
+
+// Reads a label attribute for each channel the block reports, or until the DSP rejects the channel number
+// if the block doesn't report a count. Returns an empty map if the block doesn't have the attribute at all.
+func readLabelsDo(socketKey string, instanceTag string, attribute string, policy retryPolicy) (map[string]string, error) {
+	numChannels, err := getCountDo(socketKey, instanceTag, labelCounts[attribute], policy)
+	if commandRejected(err) {
+		numChannels = maxLabelChannels
+	} else if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	for channel := 1; channel <= numChannels; channel++ {
+		index := strconv.Itoa(channel)
+		label, err := getAttributeDo(socketKey, instanceTag, attribute, []string{index}, "string", policy)
+		if commandRejected(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		labels[index] = label
+	}
+	return labels, nil
+}
+
+// Returns a copy of the cached labels of an instance tag.
+func cachedLabels(socketKey string, instanceTag string, attribute string) (map[string]string, bool) {
+	blockMetadataMutex.Lock()
+	defer blockMetadataMutex.Unlock()
+	labels, found := blockMetadataCache[socketKey][instanceTag].labels[attribute]
+	return maps.Clone(labels), found
+}
+
+// Drops the cached labels of an instance tag after one of them is written, so they are read again next time.
+func forgetLabels(socketKey string, instanceTag string, attribute string) {
+	_, isLabel := labelCounts[attribute]
+	if !isLabel {
+		return
+	}
+	blockMetadataMutex.Lock()
+	defer blockMetadataMutex.Unlock()
+	delete(blockMetadataCache[socketKey][instanceTag].labels, attribute)
+}
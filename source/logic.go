@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Logic I/O blocks connect the DSP to occupancy sensors, LED rings, relays and EX-LOGIC expanders.
+// Logic State blocks are handled by logicselector and audiomode in driver.go.
+
+// Returns true if the logic input is active.
+// The input is subscribed to on the first call, so later changes are reported by GET events.
+func getLogicInput(socketKey string, instanceTag string, channel string) (string, error) {
+	function := "getLogicInput"
+
+	err := validateIndices([]string{channel})
+	if err != nil {
+		errMsg := function + " - 5tyc0qa - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return `"unknown"`, errors.New(errMsg)
+	}
+
+	return runGet(socketKey, function, "logicinput", func(policy retryPolicy) (string, error) {
+		return getSubscribedStateDo(socketKey, instanceTag, "state", channel, policy)
+	})
+}
+
+// Returns true if the logic output is on.
+func getLogicOutput(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getLogicOutput", "logicoutput", "Logic Output", "state", instanceTag, channel)
+}
+
+// Gets the level of a control voltage channel.
+func getControlVoltage(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getControlVoltage", "controlvoltage", "Control Voltage", "level", instanceTag, channel)
+}
+
+// Returns true if the flip-flop is set.
+func getFlipFlop(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getFlipFlop", "flipflop", "Flip-Flop", "state", instanceTag, channel)
+}
+
+// Turns a logic output on (true) or off (false).
+func setLogicOutput(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setLogicOutput", "logicoutput", "Logic Output", "state", instanceTag, state, channel)
+}
+
+// Sets the level of a control voltage channel.
+func setControlVoltage(socketKey string, instanceTag string, channel string, level string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setControlVoltage", "controlvoltage", "Control Voltage", "level", instanceTag, level, channel)
+}
+
+// Sets (true) or resets (false) a flip-flop.
+func setFlipFlop(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setFlipFlop", "flipflop", "Flip-Flop", "state", instanceTag, state, channel)
+}
@@ -46,6 +46,76 @@ func doDeviceSpecificSet(socketKey string, setting string, arg1 string, arg2 str
 		return setLogicSelector(socketKey, arg1, arg2, arg3)
 	case "audiomode":
 		return setAudioMode(socketKey, arg1, arg2)
+	case "mixerinputlevel":
+		return setMixerInputLevel(socketKey, arg1, arg2, arg3)
+	case "mixerinputmute":
+		return setMixerInputMute(socketKey, arg1, arg2, arg3)
+	case "mixeroutputlevel":
+		return setMixerOutputLevel(socketKey, arg1, arg2)
+	case "mixercrosspoint":
+		return setMixerCrosspoint(socketKey, arg1, arg2, arg3)
+	case "peqgain", "peqfrequency", "peqbandwidth", "peqtype", "peqbandbypass", "peqbypass", "geqgain", "geqbandbypass", "geqbypass":
+		return setEQSetting(socketKey, setting, arg1, arg2, arg3)
+	case "dynamics":
+		return setDynamics(socketKey, arg1, arg2, arg3)
+	case "aecenable", "aecnlpmode", "aecnoisereduction", "aecnoisereductionlevel", "aecagc", "aecconferencemode":
+		return setAECSetting(socketKey, setting, arg1, arg2, arg3)
+	case "automixerchannel":
+		return setAutoMixerChannel(socketKey, arg1, arg2, arg3)
+	case "automixermanual":
+		return setAutoMixerManual(socketKey, arg1, arg2, arg3)
+	case "automixernom":
+		return setAutoMixerNOM(socketKey, arg1, arg2)
+	case "automixerlastmichold":
+		return setAutoMixerLastMicHold(socketKey, arg1, arg2)
+	case "duckerlevel", "duckerthreshold", "duckersensemute", "duckerinputmute", "duckerbypass":
+		return setDuckerSetting(socketKey, setting, arg1, arg2)
+	case "ancbypass", "ancmingain", "ancmaxgain":
+		return setANCSetting(socketKey, setting, arg1, arg2)
+	case "delay":
+		return setDelay(socketKey, arg1, arg2, arg3)
+	case "delaybypass":
+		return setDelayBypass(socketKey, arg1, arg2, arg3)
+	case "tonefrequency", "tonelevel", "tonemute", "noiselevel", "noisemute", "noisetype":
+		return setGeneratorSetting(socketKey, setting, arg1, arg2)
+	case "logicoutput":
+		return setLogicOutput(socketKey, arg1, arg2, arg3)
+	case "controlvoltage":
+		return setControlVoltage(socketKey, arg1, arg2, arg3)
+	case "flipflop":
+		return setFlipFlop(socketKey, arg1, arg2, arg3)
+	case "inputgain", "inputphantom", "inputlevel", "inputmute", "inputlabel":
+		return setInputSetting(socketKey, setting, arg1, arg2, arg3)
+	case "outputlevel", "outputmute", "outputinvert", "outputlabel":
+		return setOutputSetting(socketKey, setting, arg1, arg2, arg3)
+	case "usblevel":
+		return setUSBLevel(socketKey, arg1, arg2, arg3)
+	case "usbmute":
+		return setUSBMute(socketKey, arg1, arg2, arg3)
+	case "bluetoothdiscoverable":
+		return setBluetoothDiscoverable(socketKey, arg1, arg2)
+	case "bluetoothdisconnect":
+		return setBluetoothDisconnect(socketKey, arg1)
+	case "bluetoothclearpairings":
+		return setBluetoothClearPairings(socketKey, arg1)
+	case "combinerwall":
+		return setCombinerWall(socketKey, arg1, arg2, arg3)
+	case "combinergroup":
+		return setCombinerGroup(socketKey, arg1, arg2, arg3)
+	case "combinersource":
+		return setCombinerSource(socketKey, arg1, arg2, arg3)
+	case "combinerlevel":
+		return setCombinerLevel(socketKey, arg1, arg2, arg3)
+	case "commandstring":
+		return setCommandString(socketKey, arg1, arg2)
+	case "parlemute", "parleledmutedcolor", "parleledunmutedcolor", "parleledbrightness":
+		return setParleSetting(socketKey, setting, arg1, arg2)
+	case "parlezone":
+		return setParleZone(socketKey, arg1, arg2, arg3)
+	case "block":
+		return setBlockAttribute(socketKey, arg1, arg2, arg3)
+	case "raw":
+		return setRaw(socketKey, arg1)
 	}
 
 	// If we get here, we didn't recognize the setting.  Send an error back to the config writer who had a bad URL.
@@ -86,6 +156,141 @@ func doDeviceSpecificGet(socketKey string, setting string, arg1 string, arg2 str
 	case "audiomode":
 		value, err := getAudioMode(socketKey, arg1)
 		return value, err
+	case "aliases":
+		value, err := getAliases(socketKey, arg1)
+		return value, err
+	case "mixerinputlevel":
+		value, err := getMixerInputLevel(socketKey, arg1, arg2)
+		return value, err
+	case "mixerinputmute":
+		value, err := getMixerInputMute(socketKey, arg1, arg2)
+		return value, err
+	case "mixeroutputlevel":
+		value, err := getMixerOutputLevel(socketKey, arg1)
+		return value, err
+	case "mixercrosspoint":
+		value, err := getMixerCrosspoint(socketKey, arg1, arg2)
+		return value, err
+	case "peqgain", "peqfrequency", "peqbandwidth", "peqtype", "peqbandbypass", "peqbypass", "geqgain", "geqfrequency", "geqbandbypass", "geqbypass":
+		value, err := getEQSetting(socketKey, setting, arg1, arg2)
+		return value, err
+	case "dynamics":
+		value, err := getDynamics(socketKey, arg1)
+		return value, err
+	case "aec":
+		value, err := getAEC(socketKey, arg1, arg2)
+		return value, err
+	case "aecenable", "aecnlpmode", "aecnoisereduction", "aecnoisereductionlevel", "aecagc", "aecconferencemode":
+		value, err := getAECSetting(socketKey, setting, arg1, arg2)
+		return value, err
+	case "automixerchannel":
+		value, err := getAutoMixerChannel(socketKey, arg1, arg2)
+		return value, err
+	case "automixermanual":
+		value, err := getAutoMixerManual(socketKey, arg1, arg2)
+		return value, err
+	case "automixergate":
+		value, err := getAutoMixerGate(socketKey, arg1, arg2)
+		return value, err
+	case "automixernom":
+		value, err := getAutoMixerNOM(socketKey, arg1)
+		return value, err
+	case "automixerlastmichold":
+		value, err := getAutoMixerLastMicHold(socketKey, arg1)
+		return value, err
+	case "events":
+		value, err := getEvents(socketKey)
+		return value, err
+	case "duckerlevel", "duckerthreshold", "duckersensemute", "duckerinputmute", "duckerbypass":
+		value, err := getDuckerSetting(socketKey, setting, arg1)
+		return value, err
+	case "anc":
+		value, err := getANC(socketKey, arg1)
+		return value, err
+	case "ancbypass", "ancmingain", "ancmaxgain", "ancgain", "ancambientlevel":
+		value, err := getANCSetting(socketKey, setting, arg1)
+		return value, err
+	case "delay":
+		value, err := getDelay(socketKey, arg1, arg2)
+		return value, err
+	case "delaybypass":
+		value, err := getDelayBypass(socketKey, arg1, arg2)
+		return value, err
+	case "tonefrequency", "tonelevel", "tonemute", "noiselevel", "noisemute", "noisetype":
+		value, err := getGeneratorSetting(socketKey, setting, arg1)
+		return value, err
+	case "logicinput":
+		value, err := getLogicInput(socketKey, arg1, arg2)
+		return value, err
+	case "logicoutput":
+		value, err := getLogicOutput(socketKey, arg1, arg2)
+		return value, err
+	case "controlvoltage":
+		value, err := getControlVoltage(socketKey, arg1, arg2)
+		return value, err
+	case "flipflop":
+		value, err := getFlipFlop(socketKey, arg1, arg2)
+		return value, err
+	case "inputgain", "inputphantom", "inputlevel", "inputmute", "inputlabel":
+		value, err := getInputSetting(socketKey, setting, arg1, arg2)
+		return value, err
+	case "outputlevel", "outputmute", "outputinvert", "outputlabel":
+		value, err := getOutputSetting(socketKey, setting, arg1, arg2)
+		return value, err
+	case "labels":
+		value, err := getLabels(socketKey, arg1, arg2)
+		return value, err
+	case "channels":
+		value, err := getChannels(socketKey, arg1)
+		return value, err
+	case "usbstatus":
+		value, err := getUSBStatus(socketKey, arg1)
+		return value, err
+	case "usblevel":
+		value, err := getUSBLevel(socketKey, arg1, arg2)
+		return value, err
+	case "usbmute":
+		value, err := getUSBMute(socketKey, arg1, arg2)
+		return value, err
+	case "streamstatus":
+		value, err := getStreamStatus(socketKey, arg1)
+		return value, err
+	case "networkstatus":
+		value, err := getNetworkStatus(socketKey)
+		return value, err
+	case "bluetooth":
+		value, err := getBluetooth(socketKey, arg1)
+		return value, err
+	case "bluetoothdiscoverable":
+		value, err := getBluetoothDiscoverable(socketKey, arg1)
+		return value, err
+	case "combinerwalls":
+		value, err := getCombinerWalls(socketKey, arg1)
+		return value, err
+	case "combinergroups":
+		value, err := getCombinerGroups(socketKey, arg1)
+		return value, err
+	case "combinersource":
+		value, err := getCombinerSource(socketKey, arg1, arg2)
+		return value, err
+	case "combinerlevel":
+		value, err := getCombinerLevel(socketKey, arg1, arg2)
+		return value, err
+	case "commandstrings":
+		value, err := getCommandStrings(socketKey, arg1)
+		return value, err
+	case "parlemute", "parleledmutedcolor", "parleledunmutedcolor", "parleledbrightness":
+		value, err := getParleSetting(socketKey, setting, arg1)
+		return value, err
+	case "parletalker":
+		value, err := getParleTalker(socketKey, arg1)
+		return value, err
+	case "parlezone":
+		value, err := getParleZone(socketKey, arg1, arg2)
+		return value, err
+	case "block":
+		value, err := getBlockAttribute(socketKey, arg1, arg2)
+		return value, err
 	case "healthcheck":
 		value, err := healthCheck(socketKey)
 		return value, err
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Standard Mixer blocks sum several inputs (e.g. program audio and microphones) into one output.
+// Levels use the same 0-100 volume curve as volume, and mutes and crosspoints are true or false.
+
+// Gets the level of a standard mixer input. Returns a value between 0 and 100.
+func getMixerInputLevel(socketKey string, instanceTag string, input string) (string, error) {
+	return runGet(socketKey, "getMixerInputLevel", "mixerinputlevel", func(policy retryPolicy) (string, error) {
+		return getLevelAttributeDo(socketKey, instanceTag, "inputLevel", []string{input}, policy)
+	})
+}
+
+// Returns true if the standard mixer input is muted.
+func getMixerInputMute(socketKey string, instanceTag string, input string) (string, error) {
+	return runGet(socketKey, "getMixerInputMute", "mixerinputmute", func(policy retryPolicy) (string, error) {
+		return getStateAttributeDo(socketKey, instanceTag, "inputMute", []string{input}, policy)
+	})
+}
+
+// Gets the level of the standard mixer output. Returns a value between 0 and 100.
+func getMixerOutputLevel(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getMixerOutputLevel", "mixeroutputlevel", func(policy retryPolicy) (string, error) {
+		return getLevelAttributeDo(socketKey, instanceTag, "outputLevel", nil, policy)
+	})
+}
+
+// Returns true if the standard mixer input is routed to the output.
+func getMixerCrosspoint(socketKey string, instanceTag string, input string) (string, error) {
+	return runGet(socketKey, "getMixerCrosspoint", "mixercrosspoint", func(policy retryPolicy) (string, error) {
+		return getStateAttributeDo(socketKey, instanceTag, "crosspoint", []string{input}, policy)
+	})
+}
+
+// Sets the level of a standard mixer input. Takes a value from 0-100.
+func setMixerInputLevel(socketKey string, instanceTag string, input string, volume string) (string, error) {
+	function := "setMixerInputLevel"
+
+	volume, err := validateVolume(volume)
+	if err != nil {
+		errMsg := function + " - 6hdn2qa - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, "mixerinputlevel", func(policy retryPolicy) (string, error) {
+		return setLevelAttributeDo(socketKey, instanceTag, "inputLevel", []string{input}, volume, policy)
+	})
+}
+
+// Sets mute to true or false for a standard mixer input.
+func setMixerInputMute(socketKey string, instanceTag string, input string, state string) (string, error) {
+	return runSet(socketKey, "setMixerInputMute", "mixerinputmute", func(policy retryPolicy) (string, error) {
+		return setStateAttributeDo(socketKey, instanceTag, "inputMute", []string{input}, state, policy)
+	})
+}
+
+// Sets the level of the standard mixer output. Takes a value from 0-100.
+func setMixerOutputLevel(socketKey string, instanceTag string, volume string) (string, error) {
+	function := "setMixerOutputLevel"
+
+	volume, err := validateVolume(volume)
+	if err != nil {
+		errMsg := function + " - 0vkw5rj - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+
+	return runSet(socketKey, function, "mixeroutputlevel", func(policy retryPolicy) (string, error) {
+		return setLevelAttributeDo(socketKey, instanceTag, "outputLevel", nil, volume, policy)
+	})
+}
+
+// Routes a standard mixer input to the output (true) or removes it (false).
+func setMixerCrosspoint(socketKey string, instanceTag string, input string, state string) (string, error) {
+	return runSet(socketKey, "setMixerCrosspoint", "mixercrosspoint", func(policy retryPolicy) (string, error) {
+		return setStateAttributeDo(socketKey, instanceTag, "crosspoint", []string{input}, state, policy)
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+// Output block settings, for designs without a Level block in front of the outputs.
+// Level is in dB and invert flips the polarity of the channel.
+//
+//	GET/PUT :address/outputinvert/:instanceTag/:channel
+var outputSettings = map[string]string{
+	"outputlevel":  "level",
+	"outputmute":   "mute",
+	"outputinvert": "invert",
+	"outputlabel":  "label",
+}
+
+// Gets an output setting of a channel of the specified instance tag.
+func getOutputSetting(socketKey string, setting string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getOutputSetting", setting, "Output", outputSettings[setting], instanceTag, channel)
+}
+
+// Sets an output setting of a channel of the specified instance tag.
+func setOutputSetting(socketKey string, setting string, instanceTag string, channel string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setOutputSetting", setting, "Output", outputSettings[setting], instanceTag, value, channel)
+}
@@ -0,0 +1,50 @@
+package main
+
+// Parlé TCM ceiling microphones show up as blocks with mute, talker tracking, LED and coverage zone attributes.
+// The mic LED shows ledMutedColor or ledUnmutedColor depending on mute, so once the colors are set the
+// LED follows the privacy state set through parlemute.
+
+// LED colors a Parlé microphone can show.
+var ledColors = []string{"RED", "GREEN", "BLUE", "YELLOW", "CYAN", "MAGENTA", "WHITE", "OFF"}
+
+// Parlé microphone settings, mapped to their TTP attribute. Brightness is 0-100.
+//
+//	GET/PUT :address/parleledmutedcolor/:instanceTag
+var parleSettings = map[string]string{
+	"parlemute":            "mute",
+	"parleledmutedcolor":   "ledMutedColor",
+	"parleledunmutedcolor": "ledUnmutedColor",
+	"parleledbrightness":   "ledBrightness",
+}
+
+// Gets a Parlé microphone setting of the specified instance tag.
+func getParleSetting(socketKey string, setting string, instanceTag string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getParleSetting", setting, "Parle Microphone", parleSettings[setting], instanceTag)
+}
+
+// Sets a Parlé microphone setting of the specified instance tag.
+func setParleSetting(socketKey string, setting string, instanceTag string, value string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setParleSetting", setting, "Parle Microphone", parleSettings[setting], instanceTag, value)
+}
+
+// Gets the position of the active talker as a JSON object of angles in degrees, e.g. {"talkerAzimuth":135,"talkerElevation":40}.
+func getParleTalker(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getParleTalker", "parletalker", func(policy retryPolicy) (string, error) {
+		var attributes []blockAttribute
+		for _, name := range []string{"talkerAzimuth", "talkerElevation"} {
+			attribute, _ := blockRegistry["Parle Microphone"].attribute(name)
+			attributes = append(attributes, attribute)
+		}
+		return getAttributesObjectDo(socketKey, instanceTag, attributes, nil, policy)
+	})
+}
+
+// Returns true if the coverage zone of the microphone is enabled.
+func getParleZone(socketKey string, instanceTag string, zone string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getParleZone", "parlezone", "Parle Microphone", "zoneEnable", instanceTag, zone)
+}
+
+// Enables (true) or disables (false) a coverage zone of the microphone.
+func setParleZone(socketKey string, instanceTag string, zone string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setParleZone", "parlezone", "Parle Microphone", "zoneEnable", instanceTag, state, zone)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Commands the raw endpoint sends when BIAMP_RAW_ALLOWLIST isn't set. Read only by default.
+const defaultRawAllowlist = "* get"
+
+// The body of a raw request.
+type rawRequest struct {
+	Token   string `json:"token"`
+	Command string `json:"command"`
+}
+
+// The parsed response to a raw request.
+type rawResponse struct {
+	Response string `json:"response"`
+	Value    string `json:"value,omitempty"`
+}
+
+// Sends an arbitrary TTP command so integrators can reach attributes the driver doesn't support yet.
+// body is a JSON object with the token from BIAMP_RAW_TOKEN and the command, e.g.
+//
+//	{"token": "secret", "command": "Level1 get level 1"}
+//
+// The endpoint is disabled unless BIAMP_RAW_TOKEN is set, and only commands matching
+// BIAMP_RAW_ALLOWLIST are sent. The allowlist is a comma separated list of "instanceTag verb"
+// patterns using * and ? wildcards, e.g. "* get,Level* set". DEVICE reboot is never allowed.
+func setRaw(socketKey string, body string) (string, error) {
+	function := "setRaw"
+
+	instanceTag, verb, command, err := checkRawRequest(body)
+	if err != nil {
+		errMsg := function + " - 8fhs2kd - " + err.Error()
+		framework.AddToErrors(socketKey, errMsg)
+		return "notok", errors.New(errMsg)
+	}
+	framework.Log(function + " - sending raw " + verb + " to " + instanceTag)
+
+	do := func(policy retryPolicy) (string, error) {
+		return setRawDo(socketKey, command, verb, policy)
+	}
+	if verb == "get" {
+		return runGet(socketKey, function, "raw", do)
+	}
+
+	// Other verbs, like increment and toggle, may not be safe to send twice so they are only tried once
+	err = connect(socketKey, function)
+	if err != nil {
+		return "notok", err
+	}
+	value, err := do(retryPolicyFor("raw"))
+	if err != nil {
+		framework.AddToErrors(socketKey, function+" - 3kdn8wq - "+err.Error())
+		return "notok", err
+	}
+	return value, nil
+}
+
+// Sends the raw command and returns the DSP's response along with the value or list it carried.
+func setRawDo(socketKey string, command string, verb string, policy retryPolicy) (string, error) {
+	function := "setRawDo"
+
+	cmdType := "command"
+	respType := "none"
+	if verb == "get" {
+		cmdType = "query"
+		respType = "any"
+	}
+
+	resp, err := sendAndValidateResponse(socketKey, command+"\r", cmdType, respType, policy)
+
+	if err != nil {
+		return resp, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + resp)
+
+	parsed := rawResponse{Response: resp}
+	_, value, found := strings.Cut(resp, "\"value\":")
+	if !found {
+		_, value, found = strings.Cut(resp, "\"list\":")
+	}
+	if found {
+		parsed.Value = strings.Trim(value, "\"")
+	}
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	return string(encoded), nil
+}
+
+// Checks the token and the allowlist. Returns the command's instance tag and verb along with the command itself.
+func checkRawRequest(body string) (string, string, string, error) {
+	token := os.Getenv("BIAMP_RAW_TOKEN")
+	if token == "" {
+		return "", "", "", errors.New("raw commands are disabled. Set BIAMP_RAW_TOKEN to enable them")
+	}
+
+	var request rawRequest
+	err := json.Unmarshal([]byte(body), &request)
+	if err != nil {
+		return "", "", "", errors.New("body must be a JSON object with token and command: " + err.Error())
+	}
+	if subtle.ConstantTimeCompare([]byte(request.Token), []byte(token)) != 1 {
+		return "", "", "", errors.New("invalid token")
+	}
+
+	command := strings.TrimSpace(request.Command)
+	if strings.ContainsAny(command, "\r\n") {
+		return "", "", "", errors.New("only one command can be sent at a time")
+	}
+	instanceTag, verb := splitRawCommand(command)
+	if instanceTag == "" || verb == "" {
+		return "", "", "", errors.New("command must start with an instance tag and a verb: " + command)
+	}
+	if !rawCommandAllowed(instanceTag, verb) {
+		return "", "", "", errors.New("command is not in the raw allowlist: " + instanceTag + " " + verb)
+	}
+
+	return instanceTag, verb, command, nil
+}
+
+// Returns the instance tag and verb of a TTP command. Instance tags with spaces are quoted in TTP.
+func splitRawCommand(command string) (string, string) {
+	var instanceTag, rest string
+	if strings.HasPrefix(command, "\"") {
+		tag, after, found := strings.Cut(command[1:], "\"")
+		if !found {
+			return "", ""
+		}
+		instanceTag, rest = tag, after
+	} else {
+		instanceTag, rest, _ = strings.Cut(command, " ")
+	}
+	verb, _, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	return instanceTag, verb
+}
+
+// Checks an instance tag and verb against BIAMP_RAW_ALLOWLIST.
+func rawCommandAllowed(instanceTag string, verb string) bool {
+	if instanceTag == "DEVICE" && verb == "reboot" {
+		return false
+	}
+
+	allowlist := os.Getenv("BIAMP_RAW_ALLOWLIST")
+	if allowlist == "" {
+		allowlist = defaultRawAllowlist
+	}
+	for _, entry := range strings.Split(allowlist, ",") {
+		tagPattern, verbPattern, _ := strings.Cut(strings.TrimSpace(entry), " ")
+		tagMatch, tagErr := path.Match(tagPattern, instanceTag)
+		verbMatch, verbErr := path.Match(strings.TrimSpace(verbPattern), verb)
+		if tagErr == nil && verbErr == nil && tagMatch && verbMatch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Controls how hard the driver tries before giving up on the DSP.
+// Tesira Servers can be slow to answer while Fortes answer almost immediately, so every value
+// can be tuned with environment variables, either for all settings or for a single setting:
+//
+//	BIAMP_RETRY_ATTEMPTS=3          applies to every setting
+//	BIAMP_RETRY_ATTEMPTS_VOLUME=5   applies only to volume and wins over the line above
+//
+// The variables are BIAMP_RETRY_ATTEMPTS, BIAMP_RETRY_BACKOFF_MS, BIAMP_RETRY_JITTER_MS,
+// BIAMP_READ_ATTEMPTS and BIAMP_READ_DEADLINE_MS.
+type retryPolicy struct {
+	attempts     int           // times an operation is tried before giving up
+	backoff      time.Duration // wait before the first retry, doubled for every retry after that
+	jitter       time.Duration // up to this much random time is added to every wait
+	readAttempts int           // reads made looking for a valid response to one command
+	readDeadline time.Duration // time spent reading a response to one command before giving up, 0 for no limit
+}
+
+// The behaviour the driver had before the policy became configurable.
+var defaultRetryPolicy = retryPolicy{
+	attempts:     2,
+	backoff:      1 * time.Second,
+	jitter:       0,
+	readAttempts: 5,
+	readDeadline: 0,
+}
+
+var retryPolicies = make(map[string]retryPolicy)
+var retryPoliciesMutex sync.Mutex
+
+// Returns the retry policy for a setting, reading the environment the first time the setting is used.
+func retryPolicyFor(setting string) retryPolicy {
+	retryPoliciesMutex.Lock()
+	defer retryPoliciesMutex.Unlock()
+
+	policy, found := retryPolicies[setting]
+	if found {
+		return policy
+	}
+
+	policy = defaultRetryPolicy
+	policy.attempts = envInt(setting, "BIAMP_RETRY_ATTEMPTS", policy.attempts, 1)
+	policy.backoff = envMilliseconds(setting, "BIAMP_RETRY_BACKOFF_MS", policy.backoff)
+	policy.jitter = envMilliseconds(setting, "BIAMP_RETRY_JITTER_MS", policy.jitter)
+	policy.readAttempts = envInt(setting, "BIAMP_READ_ATTEMPTS", policy.readAttempts, 1)
+	policy.readDeadline = envMilliseconds(setting, "BIAMP_READ_DEADLINE_MS", policy.readDeadline)
+
+	retryPolicies[setting] = policy
+	return policy
+}
+
+// Waits before the given retry (1 for the first retry) using exponential backoff plus jitter.
+func (policy retryPolicy) wait(retry int) {
+	delay := policy.backoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+	}
+	if policy.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(policy.jitter)))
+	}
+	time.Sleep(delay)
+}
+
+// Looks up an environment variable, preferring the setting specific version (e.g. BIAMP_RETRY_ATTEMPTS_VOLUME).
+func envLookup(setting string, name string) (string, string, bool) {
+	if setting != "" {
+		specificName := name + "_" + strings.ToUpper(setting)
+		value, found := os.LookupEnv(specificName)
+		if found {
+			return specificName, value, true
+		}
+	}
+	value, found := os.LookupEnv(name)
+	return name, value, found
+}
+
+// Reads an integer from the environment. Falls back to the default if it's missing, invalid, or below min.
+func envInt(setting string, name string, defaultValue int, min int) int {
+	function := "envInt"
+
+	variable, value, found := envLookup(setting, name)
+	if !found {
+		return defaultValue
+	}
+	intValue, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || intValue < min {
+		framework.Log(fmt.Sprintf(function+" - 6xkd0qe - ignoring invalid %s: %s", variable, value))
+		return defaultValue
+	}
+	return intValue
+}
+
+// Reads a duration in milliseconds from the environment. Falls back to the default if it's missing or invalid.
+func envMilliseconds(setting string, name string, defaultValue time.Duration) time.Duration {
+	return time.Duration(envInt(setting, name, int(defaultValue/time.Millisecond), 0)) * time.Millisecond
+}
+
+// Returned (wrapped) by operations when the request itself is bad. Retrying won't help, so these aren't retried.
+var errInvalidArgument = errors.New("invalid argument")
+
+// Runs a get against the DSP with retries. Returns "unknown" if every attempt failed.
+func runGet(socketKey string, function string, setting string, do func(policy retryPolicy) (string, error)) (string, error) {
+	return runOperation(socketKey, function, setting, `"unknown"`, do)
+}
+
+// Runs a set against the DSP with retries. Returns "notok" if every attempt failed.
+func runSet(socketKey string, function string, setting string, do func(policy retryPolicy) (string, error)) (string, error) {
+	return runOperation(socketKey, function, setting, "notok", do)
+}
+
+// Connects to the DSP if needed, then calls do until it succeeds or the setting's retry policy runs out.
+// An attempt failed if do returned an error. When every attempt has failed, or the error wraps errInvalidArgument, the last error is recorded
+// and returned along with failedValue so callers always see the same result for a failure.
+func runOperation(socketKey string, function string, setting string, failedValue string, do func(policy retryPolicy) (string, error)) (string, error) {
+	policy := retryPolicyFor(setting)
+
+	var err error
+	for attempt := 1; attempt <= policy.attempts; attempt++ {
+		err = connect(socketKey, function)
+		if err == nil {
+			var value string
+			value, err = do(policy)
+			if err == nil { // Succeeded
+				return value, nil
+			}
+			if errors.Is(err, errInvalidArgument) {
+				break
+			}
+		}
+
+		// Something went wrong - perhaps try again
+		if attempt < policy.attempts {
+			framework.Log(function + " - fq3sdvc - retrying " + setting + " operation: " + err.Error())
+			policy.wait(attempt)
+		}
+	}
+
+	errMsg := fmt.Sprintf(function+" - f839dk4 - giving up: %v", err.Error())
+	framework.AddToErrors(socketKey, errMsg)
+	return failedValue, err
+}
+
+// Negotiates a new connection to the DSP if the framework doesn't have one open.
+func connect(socketKey string, function string) error {
+	connected := framework.CheckConnectionsMapExists(socketKey)
+	if !connected {
+		negotiation := loginNegotiation(socketKey)
+		if !negotiation {
+			errMsg := fmt.Sprintf(function + " - h3okxu3 - error connecting")
+			framework.AddToErrors(socketKey, errMsg)
+			return errors.New(errMsg)
+		}
+		forgetSubscriptions(socketKey)
+		forgetBlockMetadata(socketKey)
+		forgetWatchedStreams(socketKey)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes shared by Dante and AVB input and output blocks, describing the network flow carrying their audio.
+var streamAttributes = []blockAttribute{
+	{name: "subscribed", indices: 0, valueType: "state", readable: true},
+	{name: "connected", indices: 0, valueType: "state", readable: true},
+	{name: "error", indices: 0, valueType: "string", readable: true},
+	{name: "level", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+	{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "numChannels", indices: 0, valueType: "number", readable: true},
+}
+
+// Stream blocks that reported connected through streamstatus, which healthcheck watches along with BIAMP_HEALTH_STREAMS.
+var watchedStreams = make(map[string]map[string]bool) // socketKey -> instance tag -> watched
+var watchedStreamsMutex sync.Mutex
+
+// Gets the status of a Dante or AVB block's stream as a JSON object, e.g. {"subscribed":true,"connected":false,"error":"No Flow"}.
+// Only the attributes the block reports are included. connected is subscribed to, so a dropped flow shows up in GET events,
+// and from then on healthcheck reports false while the stream isn't connected.
+func getStreamStatus(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getStreamStatus", "streamstatus", func(policy retryPolicy) (string, error) {
+		return getStreamStatusDo(socketKey, instanceTag, policy)
+	})
+}
+
+// Reads the stream status attributes of the block.
+func getStreamStatusDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
+	function := "getStreamStatusDo"
+
+	status := make(map[string]any)
+	connected, err := getSubscribedAttributeDo(socketKey, instanceTag, "connected", "", "state", policy)
+	if err == nil {
+		status["connected"] = connected == "true"
+		// Only blocks that report connected can be checked by healthcheck
+		watchedStreamsMutex.Lock()
+		if watchedStreams[socketKey] == nil {
+			watchedStreams[socketKey] = make(map[string]bool)
+		}
+		watchedStreams[socketKey][instanceTag] = true
+		watchedStreamsMutex.Unlock()
+	} else if !commandRejected(err) {
+		return `"unknown"`, err
+	}
+
+	for _, attribute := range []string{"subscribed", "error"} {
+		stream, _ := blockRegistry["Dante Input"].attribute(attribute)
+		value, err := getAttributeDo(socketKey, instanceTag, stream.name, nil, stream.valueType, policy)
+		if commandRejected(err) {
+			continue
+		}
+		if err != nil {
+			return `"unknown"`, err
+		}
+		if stream.valueType == "state" {
+			status[stream.name] = value == "true"
+		} else {
+			status[stream.name] = value
+		}
+	}
+
+	if len(status) == 0 {
+		errMsg := function + " - 3bzk7wu - " + instanceTag + " doesn't report a stream status"
+		return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// A new connection may mean a new design was loaded, so streams are only watched again once they are read.
+func forgetWatchedStreams(socketKey string) {
+	watchedStreamsMutex.Lock()
+	defer watchedStreamsMutex.Unlock()
+	delete(watchedStreams, socketKey)
+}
+
+// Gets the link status of each of the DSP's network interfaces as a JSON object keyed by interface,
+// e.g. {"control":"LINK_1_GB","dante":"LINK_DOWN"}.
+func getNetworkStatus(socketKey string) (string, error) {
+	return runGet(socketKey, "getNetworkStatus", "networkstatus", func(policy retryPolicy) (string, error) {
+		return getNetworkStatusDo(socketKey, policy)
+	})
+}
+
+// Reads the link status of each interface and encodes it.
+func getNetworkStatusDo(socketKey string, policy retryPolicy) (string, error) {
+	function := "getNetworkStatusDo"
+
+	links, err := readLinkStatusDo(socketKey, policy)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	encoded, err := json.Marshal(links)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// Reads DEVICE networkStatus and picks out the link status of each interface.
+func readLinkStatusDo(socketKey string, policy retryPolicy) (map[string]string, error) {
+	function := "readLinkStatusDo"
+
+	resp, err := sendAndValidateResponse(socketKey, "DEVICE get networkStatus\r", "query", "any", policy)
+	if err != nil {
+		return nil, err
+	}
+
+	links := parseLinkStatus(resp)
+	if len(links) == 0 {
+		return nil, errors.New(function + " - 6tqp2va - no interfaces in network status: " + resp)
+	}
+	return links, nil
+}
+
+// Picks the link status of each interface out of a networkStatus response, which looks like
+//
+//	+OK "value":{... "networkInterfaceStatusWithName":[{"interfaceId":"control" "networkInterfaceStatus":{"macAddress":"..." "linkStatus":LINK_1_GB ...}} ...]}
+func parseLinkStatus(resp string) map[string]string {
+	links := make(map[string]string)
+	rest := resp
+	for {
+		var found bool
+		_, rest, found = strings.Cut(rest, "\"interfaceId\":\"")
+		if !found {
+			return links
+		}
+		var id string
+		id, rest, _ = strings.Cut(rest, "\"")
+
+		// The link status belongs to this interface only if it comes before the next one
+		section := rest
+		if next := strings.Index(rest, "\"interfaceId\":"); next >= 0 {
+			section = rest[:next]
+		}
+		_, status, found := strings.Cut(section, "\"linkStatus\":")
+		if !found {
+			continue
+		}
+		status, _, _ = strings.Cut(status, " ")
+		links[id] = strings.Trim(status, "\"}]")
+	}
+}
+
+// Returns what is wrong with the DSP's network audio, or nothing if it is healthy. Used by healthCheck once the DSP has answered,
+// so every query is made once. A Dante interface without a link is a problem, as is a stream that isn't connected.
+// The streams checked are those read through streamstatus and those listed in BIAMP_HEALTH_STREAMS,
+// a comma separated list of Dante or AVB instance tags. A stream that can't be read counts as not connected.
+func networkAudioProblems(socketKey string) []string {
+	policy := retryPolicyFor("healthcheck")
+	var problems []string
+
+	// DSPs without a networkStatus attribute, or without Dante, are skipped
+	links, err := readLinkStatusDo(socketKey, policy)
+	if err == nil {
+		var down []string
+		for id, status := range links {
+			if strings.Contains(strings.ToLower(id), "dante") && status == "LINK_DOWN" {
+				down = append(down, id)
+			}
+		}
+		sort.Strings(down)
+		for _, id := range down {
+			problems = append(problems, "no link on network interface "+id)
+		}
+	}
+
+	streams := make(map[string]bool)
+	for _, instanceTag := range strings.Split(os.Getenv("BIAMP_HEALTH_STREAMS"), ",") {
+		instanceTag = strings.TrimSpace(instanceTag)
+		if instanceTag != "" {
+			streams[instanceTag] = true
+		}
+	}
+	watchedStreamsMutex.Lock()
+	for instanceTag := range watchedStreams[socketKey] {
+		streams[instanceTag] = true
+	}
+	watchedStreamsMutex.Unlock()
+
+	var disconnected []string
+	for instanceTag := range streams {
+		value, err := getSubscribedAttributeDo(socketKey, instanceTag, "connected", "", "state", policy)
+		if err != nil || value != "true" {
+			disconnected = append(disconnected, instanceTag)
+		}
+	}
+	if len(disconnected) > 0 {
+		sort.Strings(disconnected)
+		problems = append(problems, "streams not connected: "+strings.Join(disconnected, ", "))
+	}
+
+	return problems
+}
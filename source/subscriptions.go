@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// The DSP publishes subscribed attributes whenever they change, e.g.
+//
+//	! "publishToken":"AutoMixer1_gateStatus_2" "value":true
+//
+// Publishes arrive in between the responses to our own commands, so sendAndValidateResponse hands them
+// to recordPublish. Every change is kept as an event until the orchestrator collects it from GET events.
+
+// A subscribed attribute of a block.
+type subscription struct {
+	instanceTag string
+	attribute   string
+	index       string
+	value       string // last value seen, "" until the first publish
+}
+
+// A change to a subscribed attribute, as returned by GET events.
+type changeEvent struct {
+	InstanceTag string `json:"instanceTag"`
+	Attribute   string `json:"attribute"`
+	Index       string `json:"index,omitempty"`
+	Value       string `json:"value"`
+	Time        string `json:"time"`
+}
+
+// Events kept per DSP before the oldest are dropped.
+const maxChangeEvents = 200
+
+var subscriptions = make(map[string]map[string]*subscription) // socketKey -> publish token -> subscription
+var changeEvents = make(map[string][]changeEvent)             // socketKey -> events not collected yet
+var subscriptionsMutex sync.Mutex
+
+// Returns the publish token used for an attribute. Tokens can't contain spaces or quotes.
+func publishToken(instanceTag string, attribute string, index string) string {
+	token := instanceTag + "_" + attribute
+	if index != "" {
+		token += "_" + index
+	}
+	return strings.NewReplacer(" ", "-", "\"", "").Replace(token)
+}
+
+// Subscribes to an attribute unless this connection already has. The DSP answers with the current value straight away.
+func subscribeDo(socketKey string, instanceTag string, attribute string, index string, policy retryPolicy) error {
+	function := "subscribeDo"
+	token := publishToken(instanceTag, attribute, index)
+
+	var indices []string
+	if index != "" {
+		indices = []string{index}
+	}
+	err := checkCommandParts(instanceTag, attribute, indices)
+	if err == nil {
+		err = checkIndexCounts(socketKey, instanceTag, attribute, indices)
+	}
+	if err != nil {
+		return err
+	}
+
+	subscriptionsMutex.Lock()
+	_, subscribed := subscriptions[socketKey][token]
+	if !subscribed {
+		if subscriptions[socketKey] == nil {
+			subscriptions[socketKey] = make(map[string]*subscription)
+		}
+		// Registered before sending so the first publish, which may arrive before +OK, is recognized
+		subscriptions[socketKey][token] = &subscription{instanceTag: instanceTag, attribute: attribute, index: index}
+	}
+	subscriptionsMutex.Unlock()
+	if subscribed {
+		return nil
+	}
+
+	cmdString := instanceTag + " subscribe " + attribute + " "
+	if index != "" {
+		cmdString += index + " "
+	}
+	cmdString += token + "\r"
+
+	resp, err := sendAndValidateResponse(socketKey, cmdString, "command", "none", policy)
+	if err != nil {
+		subscriptionsMutex.Lock()
+		delete(subscriptions[socketKey], token)
+		subscriptionsMutex.Unlock()
+		return err
+	}
+
+	framework.Log(function + " - Decoded Response: " + resp)
+
+	return nil
+}
+
+// Handles a publish line from the DSP, recording a change event if the value changed.
+func recordPublish(socketKey string, resp string) {
+	_, rest, found := strings.Cut(resp, "\"publishToken\":\"")
+	if !found {
+		return
+	}
+	token, rest, _ := strings.Cut(rest, "\"")
+	_, value, found := strings.Cut(rest, "\"value\":")
+	if !found {
+		return
+	}
+	value = strings.Trim(strings.TrimSpace(value), "\"")
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	sub, subscribed := subscriptions[socketKey][token]
+	if !subscribed {
+		framework.Log("Ignoring publish for unknown token: " + token)
+		return
+	}
+	updateSubscription(socketKey, sub, value)
+}
+
+// Records a value read directly for a subscribed attribute, so a change noticed by a get still becomes an event.
+func recordValue(socketKey string, instanceTag string, attribute string, index string, value string) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	sub, subscribed := subscriptions[socketKey][publishToken(instanceTag, attribute, index)]
+	if subscribed {
+		updateSubscription(socketKey, sub, strings.Trim(value, "\""))
+	}
+}
+
+// Stores a new value for a subscription and queues a change event. subscriptionsMutex must be held.
+func updateSubscription(socketKey string, sub *subscription, value string) {
+	if sub.value == value {
+		return
+	}
+	sub.value = value
+
+	events := append(changeEvents[socketKey], changeEvent{
+		InstanceTag: sub.instanceTag,
+		Attribute:   sub.attribute,
+		Index:       sub.index,
+		Value:       value,
+		Time:        time.Now().Format(time.RFC3339),
+	})
+	if len(events) > maxChangeEvents {
+		events = events[len(events)-maxChangeEvents:]
+	}
+	changeEvents[socketKey] = events
+}
+
+// The DSP drops subscriptions when the connection closes, so they are forgotten when a new connection is made.
+func forgetSubscriptions(socketKey string) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+	delete(subscriptions, socketKey)
+}
+
+// Returns the change events seen since the last call as a JSON list, oldest first.
+// Publishes are only read while the driver waits for a response, so a cheap query is sent first
+// to read any that arrived since the last command.
+func getEvents(socketKey string) (string, error) {
+	subscriptionsMutex.Lock()
+	subscribed := len(subscriptions[socketKey]) > 0
+	subscriptionsMutex.Unlock()
+
+	if subscribed {
+		// Events already collected are still returned if the DSP can't be reached
+		runGet(socketKey, "getEvents", "events", func(policy retryPolicy) (string, error) {
+			return readPublishesDo(socketKey, policy)
+		})
+	}
+
+	subscriptionsMutex.Lock()
+	events := changeEvents[socketKey]
+	delete(changeEvents, socketKey)
+	subscriptionsMutex.Unlock()
+
+	if events == nil {
+		events = []changeEvent{}
+	}
+	encoded, err := json.Marshal(events)
+	if err != nil {
+		return `"unknown"`, err
+	}
+	return string(encoded), nil
+}
+
+// Sends a query that changes nothing so sendAndValidateResponse reads the publishes waiting ahead of its response.
+func readPublishesDo(socketKey string, policy retryPolicy) (string, error) {
+	_, err := sendAndValidateResponse(socketKey, "DEVICE get hostname\r", "query", "any", policy)
+	if err != nil {
+		return `"unknown"`, err
+	}
+	return "ok", nil
+}
+
+// Subscribes to a true/false attribute if needed and reads its current value.
+// Publishes only arrive while the driver is reading from the DSP, so the value is read directly to be
+// sure it's current. A change seen this way is still recorded as an event.
+func getSubscribedStateDo(socketKey string, instanceTag string, attribute string, index string, policy retryPolicy) (string, error) {
+	value, err := getSubscribedAttributeDo(socketKey, instanceTag, attribute, index, "state", policy)
+	if err != nil {
+		return `"unknown"`, err
+	}
+	return `"` + value + `"`, nil
+}
+
+// Subscribes to an attribute if needed and reads its current value, returning the bare value like getAttributeDo.
+func getSubscribedAttributeDo(socketKey string, instanceTag string, attribute string, index string, respType string, policy retryPolicy) (string, error) {
+	err := subscribeDo(socketKey, instanceTag, attribute, index, policy)
+	if err != nil {
+		return "", err
+	}
+
+	var indices []string
+	if index != "" {
+		indices = []string{index}
+	}
+	value, err := getAttributeDo(socketKey, instanceTag, attribute, indices, respType, policy)
+	if err != nil {
+		return value, err
+	}
+
+	recordValue(socketKey, instanceTag, attribute, index, value)
+	return value, nil
+}
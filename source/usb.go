@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Dartmouth-OpenAV/microservice-framework/framework"
+)
+
+// Attributes shared by USB Input and USB Output blocks. Level is in dB.
+var usbAttributes = []blockAttribute{
+	{name: "connected", indices: 0, valueType: "state", readable: true},
+	{name: "streaming", indices: 0, valueType: "state", readable: true},
+	{name: "level", indices: 1, valueType: "number", readable: true, writable: true, min: -100, max: 12},
+	{name: "mute", indices: 1, valueType: "state", readable: true, writable: true},
+	{name: "numChannels", indices: 0, valueType: "number", readable: true},
+}
+
+// Both USB block types have the same attributes, so either one can be used to check requests.
+const usbBlockType = "USB Input"
+
+// Gets whether a USB host is connected and streaming audio as a JSON object, e.g. {"connected":true,"streaming":false}.
+// Both are subscribed to on the first call, so the PC being plugged in or unplugged shows up in GET events.
+func getUSBStatus(socketKey string, instanceTag string) (string, error) {
+	return runGet(socketKey, "getUSBStatus", "usbstatus", func(policy retryPolicy) (string, error) {
+		return getUSBStatusDo(socketKey, instanceTag, policy)
+	})
+}
+
+// Subscribes to and reads the USB connection status attributes the block has.
+func getUSBStatusDo(socketKey string, instanceTag string, policy retryPolicy) (string, error) {
+	function := "getUSBStatusDo"
+
+	status := make(map[string]bool)
+	for _, attribute := range []string{"connected", "streaming"} {
+		value, err := getSubscribedStateDo(socketKey, instanceTag, attribute, "", policy)
+		if commandRejected(err) {
+			continue
+		}
+		if err != nil {
+			return value, err
+		}
+		status[attribute] = value == `"true"`
+	}
+
+	if len(status) == 0 {
+		errMsg := function + " - 1ogw6hz - " + instanceTag + " doesn't report a USB connection status"
+		return `"unknown"`, fmt.Errorf("%w: %s", errInvalidArgument, errMsg)
+	}
+
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return `"unknown"`, err
+	}
+
+	framework.Log(function + " - Decoded Response: " + string(encoded))
+
+	return string(encoded), nil
+}
+
+// Gets the level of a USB channel in dB.
+func getUSBLevel(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getUSBLevel", "usblevel", usbBlockType, "level", instanceTag, channel)
+}
+
+// Returns true if the USB channel is muted.
+func getUSBMute(socketKey string, instanceTag string, channel string) (string, error) {
+	return getRegisteredAttribute(socketKey, "getUSBMute", "usbmute", usbBlockType, "mute", instanceTag, channel)
+}
+
+// Sets the level of a USB channel in dB.
+func setUSBLevel(socketKey string, instanceTag string, channel string, level string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setUSBLevel", "usblevel", usbBlockType, "level", instanceTag, level, channel)
+}
+
+// Sets mute to true or false for a USB channel.
+func setUSBMute(socketKey string, instanceTag string, channel string, state string) (string, error) {
+	return setRegisteredAttribute(socketKey, "setUSBMute", "usbmute", usbBlockType, "mute", instanceTag, state, channel)
+}